@@ -0,0 +1,192 @@
+package goval
+
+import "strconv"
+
+// Builder builds a Path programmatically, without parsing path string.
+// A path starts with Field, and Index, All and Key select the elements of the field just before them.
+// The zero value is an empty path.
+//
+// example.
+// p := goval.Field("Members").All().Field("Name")
+// p.String() // "Members[*].Name"
+type Builder struct {
+	Path
+}
+
+// Parent returns the parent path, or nil for the empty path.
+func (b Builder) Parent() Path {
+	if b.Path == nil {
+		return nil
+	}
+	return b.Path.Parent()
+}
+
+// Name returns the field name of the last segment, or "" for the empty path.
+func (b Builder) Name() string {
+	if b.Path == nil {
+		return ""
+	}
+	return b.Path.Name()
+}
+
+// Split returns the segments of the path, or nil for the empty path.
+func (b Builder) Split() []Path {
+	if b.Path == nil {
+		return nil
+	}
+	return b.Path.Split()
+}
+
+// Type returns the type of the last segment, or 0 for the empty path.
+func (b Builder) Type() PathType {
+	if b.Path == nil {
+		return 0
+	}
+	return b.Path.Type()
+}
+
+// String returns the path string, or "" for the empty path.
+func (b Builder) String() string {
+	if b.Path == nil {
+		return ""
+	}
+	return b.Path.String()
+}
+
+// Field starts a path with the field name.
+func Field(name string) Builder {
+	return Builder{}.Field(name)
+}
+
+// Field appends the field name to the path.
+// It panics if the name is not a word of letters, digits and underscores, which the path string can not express.
+func (b Builder) Field(name string) Builder {
+	if !regValue.MatchString(name) {
+		panic("goval: invalid field name " + strconv.Quote(name))
+	}
+	return Builder{
+		Path: &path{
+			parent: b.Path,
+			name:   name,
+			ptype:  PathTypeValue,
+		},
+	}
+}
+
+// Index selects the element of the last field by the index. negative index counts from the end.
+// It panics unless the last segment is a field, because nested indexes such as a[0][1] can not be expressed.
+func (b Builder) Index(index int) Builder {
+	return Builder{
+		Path: &pathList{
+			path:  b.lastField(),
+			index: index,
+		},
+	}
+}
+
+// All selects all elements of the last field. It panics unless the last segment is a field.
+func (b Builder) All() Builder {
+	p := b.lastField()
+	p.ptype = PathTypeCollection
+	return Builder{
+		Path: &pathListAll{
+			path: p,
+			all:  true,
+		},
+	}
+}
+
+// Key selects the map entry of the last field by the key. It panics unless the last segment is a field.
+func (b Builder) Key(key string) Builder {
+	return Builder{
+		Path: &pathMapKey{
+			path: b.lastField(),
+			key:  key,
+		},
+	}
+}
+
+// Append appends all segments of the path.
+func (b Builder) Append(p Path) Builder {
+	return Builder{
+		Path: Join(b.Path, p),
+	}
+}
+
+// lastField returns the last segment, which must be a plain field.
+func (b Builder) lastField() path {
+	p, ok := b.Path.(*path)
+	if !ok {
+		panic("goval: Index, All and Key must follow Field, got " + strconv.Quote(b.String()))
+	}
+	return *p
+}
+
+// Join joins the paths. e.g. Join(a.b, c[0].d) is a.b.c[0].d
+func Join(a, b Path) Path {
+	if b == nil {
+		return a
+	}
+	p := unwrap(a)
+	for _, seg := range b.Split() {
+		p = withParent(seg, p)
+	}
+	return p
+}
+
+// unwrap returns the last segment of the path wrapped by Builder or TextPath.
+func unwrap(p Path) Path {
+	for {
+		switch w := p.(type) {
+		case Builder:
+			p = w.Path
+		case TextPath:
+			p = w.Path
+		default:
+			return p
+		}
+	}
+}
+
+// withParent returns a copy of the path segment with the parent.
+func withParent(seg Path, parent Path) Path {
+	switch s := seg.(type) {
+	case Builder:
+		return withParent(s.Path, parent)
+	case *path:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathList:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathListAll:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathListRange:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathListUnion:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathMapKey:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathFilter:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathFieldAll:
+		c := *s
+		c.parent = parent
+		return &c
+	case *pathRecursive:
+		return &pathRecursive{Path: withParent(s.Path, parent)}
+	}
+	panic("goval: unknown path segment " + seg.String())
+}
@@ -0,0 +1,128 @@
+package goval_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+func TestBuilder(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	type team struct {
+		Name    string
+		Members []*member
+		Labels  map[string]string
+	}
+	target := &team{
+		Name:    "TEAM-A",
+		Members: []*member{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}},
+		Labels:  map[string]string{"env": "prod"},
+	}
+	parse := func(s string) goval.Path {
+		p, err := goval.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	type test struct {
+		name     string
+		path     goval.Path
+		wantPath string
+		want     []any
+	}
+	tests := []test{
+		{name: "field", path: goval.Field("Name"), wantPath: "Name", want: []any{"TEAM-A"}},
+		{name: "all", path: goval.Field("Members").All().Field("Name"), wantPath: "Members[*].Name", want: []any{"Alice", "Bob", "Carol"}},
+		{name: "index", path: goval.Field("Members").Index(2).Field("Name"), wantPath: "Members[2].Name", want: []any{"Carol"}},
+		{name: "key", path: goval.Field("Labels").Key("env"), wantPath: "Labels[env]", want: []any{"prod"}},
+		{name: "append", path: goval.Field("Members").Index(1).Append(parse("Name")), wantPath: "Members[1].Name", want: []any{"Bob"}},
+		{name: "join", path: goval.Join(parse("Members[0]"), parse("Name")), wantPath: "Members[0].Name", want: []any{"Alice"}},
+		{name: "join zero builder", path: goval.Join(goval.Builder{}, parse("Name")), wantPath: "Name", want: []any{"TEAM-A"}},
+		{name: "join nil", path: goval.Join(nil, parse("..Name")), wantPath: "..Name", want: []any{"TEAM-A", "Alice", "Bob", "Carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.path.String(); got != tt.wantPath {
+				t.Errorf("String() = %v, want %v", got, tt.wantPath)
+			}
+			got := goval.GetAll[any](target, tt.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderZeroValue(t *testing.T) {
+	var b goval.Builder
+	if got := b.String(); got != "" {
+		t.Errorf("String() = %q, want empty", got)
+	}
+	if got := b.Split(); got != nil {
+		t.Errorf("Split() = %v, want nil", got)
+	}
+	if got := b.Append(goval.Field("Name")).String(); got != "Name" {
+		t.Errorf("Append() = %v, want Name", got)
+	}
+	if got := goval.Join(goval.Field("Members"), b).String(); got != "Members" {
+		t.Errorf("Join() = %v, want Members", got)
+	}
+	if got := goval.Join(b, goval.Field("Name")).String(); got != "Name" {
+		t.Errorf("Join() = %v, want Name", got)
+	}
+	err := goval.TryEach(struct{ Name string }{}, b, func(_ any, _ goval.PathInfo) {})
+	if !errors.As(err, new(*goval.InvalidTargetError)) {
+		t.Errorf("TryEach() error = %v, want *goval.InvalidTargetError", err)
+	}
+	if err := goval.TryEach(&struct{ Name string }{}, b, func(_ any, _ goval.PathInfo) {}); err != nil {
+		t.Errorf("TryEach() error = %v, want nil", err)
+	}
+}
+
+func TestBuilderPanics(t *testing.T) {
+	tests := map[string]func(){
+		"index of zero value": func() { goval.Builder{}.Index(0) },
+		"nested index":        func() { goval.Field("Matrix").Index(1).Index(2) },
+		"key after all":       func() { goval.Field("Groups").All().Key("k") },
+		"dotted field name":   func() { goval.Field("a.b") },
+		"empty field name":    func() { goval.Field("Members").Field("") },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleField() {
+	type Member struct {
+		Name string
+	}
+	type Team struct {
+		Members []*Member
+	}
+	team := Team{
+		Members: []*Member{
+			{Name: "Alice"},
+			{Name: "Bob"},
+		},
+	}
+	path := goval.Field("Members").All().Field("Name")
+	fmt.Println(path)                              // Members[*].Name
+	fmt.Println(goval.GetAll[string](&team, path)) // [Alice Bob]
+	// Output:
+	// Members[*].Name
+	// [Alice Bob]
+}
@@ -0,0 +1,322 @@
+package goval
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Append appends the values to the slice fields specified in the path. e.g. "Members", "Teams[*].Members"
+func Append[T any](target any, path Path, values []T, opts ...Option) error {
+	return eachCollection(target, path, func(coll reflect.Value, pathInfo PathInfo) error {
+		if coll.Kind() != reflect.Slice {
+			return &InvalidOperationError{Op: "append", Path: pathInfo.ResolvedPath, Type: coll.Type()}
+		}
+		newColl := coll
+		for _, v := range values {
+			rv, err := elemValue(coll.Type(), v, pathInfo)
+			if err != nil {
+				return err
+			}
+			newColl = reflect.Append(newColl, rv)
+		}
+		pathInfo.set(newColl)
+		return nil
+	}, opts...)
+}
+
+// Insert inserts the value to the slice at the index, or to the map at the key. e.g. "Members[2]", "Labels[env]"
+func Insert[T any](target any, path Path, value T, opts ...Option) error {
+	var index int
+	var isIndex bool
+	var key string
+	switch p := unwrap(path).(type) {
+	case *pathList:
+		index, isIndex = p.index, true
+		key = strconv.Itoa(p.index)
+	case *pathMapKey:
+		key = p.key
+	default:
+		return &InvalidOperationError{Op: "insert", Path: path}
+	}
+	return eachCollection(target, collectionOf(path), func(coll reflect.Value, pathInfo PathInfo) error {
+		rv, err := elemValue(coll.Type(), value, pathInfo)
+		if err != nil {
+			return err
+		}
+		switch {
+		case coll.Kind() == reflect.Map:
+			k, err := mapKey(coll.Type().Key(), key)
+			if err != nil {
+				return &TypeMismatchError{Path: path, Want: coll.Type().Key(), Got: reflect.TypeOf(key)}
+			}
+			if coll.IsNil() {
+				m := reflect.MakeMap(coll.Type())
+				m.SetMapIndex(k, rv)
+				pathInfo.set(m)
+				return nil
+			}
+			coll.SetMapIndex(k, rv)
+			return nil
+		case coll.Kind() == reflect.Slice && isIndex:
+			index := index
+			if index < 0 { // insert before the element counted from the end
+				index += coll.Len()
+			}
+			if index < 0 || index > coll.Len() {
+				return &IndexOutOfRangeError{Path: path, Index: index, Len: coll.Len()}
+			}
+			newColl := reflect.MakeSlice(coll.Type(), 0, coll.Len()+1)
+			newColl = reflect.AppendSlice(newColl, coll.Slice(0, index))
+			newColl = reflect.Append(newColl, rv)
+			newColl = reflect.AppendSlice(newColl, coll.Slice(index, coll.Len()))
+			pathInfo.set(newColl)
+			return nil
+		}
+		return &InvalidOperationError{Op: "insert", Path: pathInfo.ResolvedPath, Type: coll.Type()}
+	}, opts...)
+}
+
+// Delete deletes the element of the slice at the index, or the entry of the map at the key. e.g. "Members[1]", "Labels[env]"
+func Delete(target any, path Path, opts ...Option) error {
+	o := newOptions(opts)
+	var index int
+	var isIndex bool
+	var key string
+	switch p := unwrap(path).(type) {
+	case *pathList:
+		index, isIndex = p.index, true
+		key = strconv.Itoa(p.index)
+	case *pathMapKey:
+		key = p.key
+	default:
+		return &InvalidOperationError{Op: "delete", Path: path}
+	}
+	return eachCollection(target, collectionOf(path), func(coll reflect.Value, pathInfo PathInfo) error {
+		switch {
+		case coll.Kind() == reflect.Map:
+			k, err := mapKey(coll.Type().Key(), key)
+			if err != nil {
+				return &TypeMismatchError{Path: path, Want: coll.Type().Key(), Got: reflect.TypeOf(key)}
+			}
+			if !coll.MapIndex(k).IsValid() {
+				if o.strict {
+					return &KeyNotFoundError{Path: path, Key: key}
+				}
+				return nil
+			}
+			coll.SetMapIndex(k, reflect.Value{})
+			return nil
+		case coll.Kind() == reflect.Slice && isIndex:
+			index := index
+			if index < 0 {
+				index += coll.Len()
+			}
+			if index < 0 || index >= coll.Len() {
+				if o.strict {
+					return &IndexOutOfRangeError{Path: path, Index: index, Len: coll.Len()}
+				}
+				return nil
+			}
+			pathInfo.set(deleteIndexes(coll, map[int]bool{index: true}))
+			return nil
+		}
+		return &InvalidOperationError{Op: "delete", Path: pathInfo.ResolvedPath, Type: coll.Type()}
+	}, opts...)
+}
+
+// DeleteWhere deletes the elements selected by the last segment of the path and matching the predicate.
+// e.g. "Members[*]", "Members[1:3]", "Members[0,2]", "Members[?(@.Age > 30)]", "Labels[env]"
+func DeleteWhere[T any](target any, path Path, pred func(v T, pathInfo PathInfo) bool, opts ...Option) error {
+	w := &walker{opts: newOptions(opts)}
+	seg := unwrap(path)
+	if r, ok := seg.(*pathRecursive); ok {
+		seg = r.Path
+	}
+	switch seg.(type) {
+	case *pathListAll, *pathFilter, *pathList, *pathListRange, *pathListUnion, *pathMapKey:
+	default:
+		return &InvalidOperationError{Op: "delete", Path: path}
+	}
+	filter, _ := seg.(*pathFilter)
+	return eachCollection(target, collectionOf(path), func(coll reflect.Value, pathInfo PathInfo) error {
+		resolved := pathInfo.ResolvedPath
+		match := func(v reflect.Value, info PathInfo) (bool, error) {
+			if filter != nil {
+				ok, err := filter.filter.eval(w, v)
+				if err != nil || !ok {
+					return false, err
+				}
+			}
+			value := w.opts.value(v, info.readOnly)
+			r, err := as[T](value, info)
+			if err != nil {
+				return false, err
+			}
+			return pred(r, info), nil
+		}
+
+		switch coll.Kind() {
+		case reflect.Map:
+			keys, err := selectedKeys(seg, coll)
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				info := pathInfo
+				info.fieldValue = coll.MapIndex(key)
+				info.mapValue = coll
+				info.mapKey = key
+				info.resolveKey(keySegment(resolved, key), keyInterface(key))
+				ok, err := match(info.fieldValue, info)
+				if err != nil {
+					return err
+				}
+				if ok {
+					coll.SetMapIndex(key, reflect.Value{})
+				}
+			}
+			return nil
+		case reflect.Slice:
+			deleted := map[int]bool{}
+			for _, i := range selectedIndexes(seg, coll.Len()) {
+				info := pathInfo
+				info.fieldValue = coll.Index(i)
+				info.resolveKey(indexSegment(resolved, i), i)
+				ok, err := match(info.fieldValue, info)
+				if err != nil {
+					return err
+				}
+				if ok {
+					deleted[i] = true
+				}
+			}
+			if len(deleted) > 0 {
+				pathInfo.set(deleteIndexes(coll, deleted))
+			}
+			return nil
+		}
+		return &InvalidOperationError{Op: "delete", Path: resolved, Type: coll.Type()}
+	}, opts...)
+}
+
+// selectedIndexes returns the indexes of the slice selected by the segment, in range and without duplicates.
+func selectedIndexes(seg Path, length int) []int {
+	var indexes []int
+	switch p := seg.(type) {
+	case *pathList:
+		indexes = []int{p.index}
+	case *pathListRange:
+		return p.indexes(length)
+	case *pathListUnion:
+		indexes = p.indexes
+	default: // all elements, tested by the filter if any.
+		indexes = make([]int, length)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+	seen := map[int]bool{}
+	selected := make([]int, 0, len(indexes))
+	for _, i := range indexes {
+		if i < 0 {
+			i += length
+		}
+		if i < 0 || i >= length || seen[i] {
+			continue
+		}
+		seen[i] = true
+		selected = append(selected, i)
+	}
+	return selected
+}
+
+// selectedKeys returns the existing keys of the map selected by the segment.
+func selectedKeys(seg Path, m reflect.Value) ([]reflect.Value, error) {
+	var keys []string
+	switch p := seg.(type) {
+	case *pathMapKey:
+		keys = []string{p.key}
+	case *pathList:
+		keys = []string{strconv.Itoa(p.index)}
+	case *pathListUnion:
+		for _, i := range p.indexes {
+			keys = append(keys, strconv.Itoa(i))
+		}
+	case *pathListRange:
+		return nil, &InvalidOperationError{Op: "delete", Path: seg, Type: m.Type()}
+	default: // all entries, tested by the filter if any.
+		return sortedMapKeys(m), nil
+	}
+	seen := map[string]bool{}
+	selected := make([]reflect.Value, 0, len(keys))
+	for _, s := range keys {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		key, err := mapKey(m.Type().Key(), s)
+		if err != nil {
+			return nil, &TypeMismatchError{Path: seg, Want: m.Type().Key(), Got: reflect.TypeOf(s)}
+		}
+		if m.MapIndex(key).IsValid() {
+			selected = append(selected, key)
+		}
+	}
+	return selected, nil
+}
+
+// collectionOf returns the path of the collection field, removing the index or key of the last segment.
+func collectionOf(p Path) Path {
+	p = unwrap(p)
+	if r, ok := p.(*pathRecursive); ok {
+		return &pathRecursive{Path: withParent(&path{name: r.Name(), ptype: PathTypeValue}, r.Parent())}
+	}
+	return withParent(&path{name: p.Name(), ptype: PathTypeValue}, p.Parent())
+}
+
+// eachCollection executes the function for each collection field specified in the path.
+func eachCollection(target any, p Path, fn func(coll reflect.Value, pathInfo PathInfo) error, opts ...Option) error {
+	return tryWrite(target, p, func(_ any, pathInfo PathInfo) error {
+		coll := pathInfo.fieldValue
+		if coll.Kind() == reflect.Interface {
+			coll = coll.Elem()
+		}
+		if !coll.IsValid() {
+			return nil
+		}
+		if !pathInfo.canSet() {
+			return pathInfo.setError(coll.Type())
+		}
+		return fn(coll, pathInfo)
+	}, opts)
+}
+
+// elemValue converts the value to the element type of the collection.
+func elemValue(collType reflect.Type, v any, pathInfo PathInfo) (reflect.Value, error) {
+	switch collType.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return reflect.Value{}, &InvalidOperationError{Op: "add element", Path: pathInfo.ResolvedPath, Type: collType}
+	}
+	elemType := collType.Elem()
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return reflect.Zero(elemType), nil
+	}
+	if !rv.Type().AssignableTo(elemType) {
+		return reflect.Value{}, &TypeMismatchError{Path: pathInfo.ResolvedPath, Want: elemType, Got: rv.Type()}
+	}
+	return rv, nil
+}
+
+// deleteIndexes returns a new slice without the elements at the indexes.
+func deleteIndexes(coll reflect.Value, indexes map[int]bool) reflect.Value {
+	newColl := reflect.MakeSlice(coll.Type(), 0, coll.Len()-len(indexes))
+	for i := 0; i < coll.Len(); i++ {
+		if !indexes[i] {
+			newColl = reflect.Append(newColl, coll.Index(i))
+		}
+	}
+	return newColl
+}
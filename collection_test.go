@@ -0,0 +1,361 @@
+package goval_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+type collMember struct {
+	Name string
+	Age  int
+}
+
+type collTeam struct {
+	Members []collMember
+	Labels  map[string]string
+	Slots   [2]string
+	Groups  map[string][]string
+}
+
+func newCollTeam() *collTeam {
+	return &collTeam{
+		Members: []collMember{
+			{Name: "Alice", Age: 25},
+			{Name: "Bob", Age: 40},
+			{Name: "Carol", Age: 31},
+		},
+		Labels: map[string]string{"env": "prod", "team": "a"},
+		Groups: map[string][]string{"dev": {"Alice"}},
+	}
+}
+
+func TestCollection(t *testing.T) {
+	type test struct {
+		name    string
+		op      func(target *collTeam) error
+		want    func(want *collTeam)
+		wantErr any
+	}
+	parse := func(s string) goval.Path {
+		p, err := goval.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tests := []test{
+		{
+			name: "append to slice",
+			op: func(target *collTeam) error {
+				return goval.Append(target, parse("Members"), []collMember{{Name: "Dave"}, {Name: "Eve"}})
+			},
+			want: func(want *collTeam) {
+				want.Members = append(want.Members, collMember{Name: "Dave"}, collMember{Name: "Eve"})
+			},
+		},
+		{
+			name: "append to slice in map",
+			op: func(target *collTeam) error {
+				return goval.Append(target, parse("Groups[dev]"), []string{"Bob"})
+			},
+			want: func(want *collTeam) {
+				want.Groups["dev"] = []string{"Alice", "Bob"}
+			},
+		},
+		{
+			name: "append to array",
+			op: func(target *collTeam) error {
+				return goval.Append(target, parse("Slots"), []string{"a"})
+			},
+			wantErr: new(*goval.InvalidOperationError),
+		},
+		{
+			name: "append mismatched type",
+			op: func(target *collTeam) error {
+				return goval.Append(target, parse("Members"), []string{"Dave"})
+			},
+			wantErr: new(*goval.TypeMismatchError),
+		},
+		{
+			name: "insert to slice",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Members[1]"), collMember{Name: "Dave"})
+			},
+			want: func(want *collTeam) {
+				want.Members = []collMember{want.Members[0], {Name: "Dave"}, want.Members[1], want.Members[2]}
+			},
+		},
+		{
+			name: "insert to end of slice",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Members[3]"), collMember{Name: "Dave"})
+			},
+			want: func(want *collTeam) {
+				want.Members = append(want.Members, collMember{Name: "Dave"})
+			},
+		},
+		{
+			name: "insert out of range",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Members[4]"), collMember{Name: "Dave"})
+			},
+			wantErr: new(*goval.IndexOutOfRangeError),
+		},
+		{
+			name: "insert to map",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Labels[region]"), "jp")
+			},
+			want: func(want *collTeam) {
+				want.Labels["region"] = "jp"
+			},
+		},
+		{
+			name: "insert without index",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Members[*]"), collMember{})
+			},
+			wantErr: new(*goval.InvalidOperationError),
+		},
+		{
+			name: "delete from slice",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Members[1]"))
+			},
+			want: func(want *collTeam) {
+				want.Members = []collMember{want.Members[0], want.Members[2]}
+			},
+		},
+		{
+			name: "insert before last of slice",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Members[-1]"), collMember{Name: "Dave"})
+			},
+			want: func(want *collTeam) {
+				want.Members = []collMember{want.Members[0], want.Members[1], {Name: "Dave"}, want.Members[2]}
+			},
+		},
+		{
+			name: "delete last of slice",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Members[-1]"))
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[:2]
+			},
+		},
+		{
+			name: "delete from map",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Labels[env]"))
+			},
+			want: func(want *collTeam) {
+				delete(want.Labels, "env")
+			},
+		},
+		{
+			name: "delete missing index",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Members[5]"))
+			},
+			want: func(want *collTeam) {},
+		},
+		{
+			name: "delete missing index in strict mode",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Members[5]"), goval.Strict())
+			},
+			wantErr: new(*goval.IndexOutOfRangeError),
+		},
+		{
+			name: "delete from array",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, parse("Slots[0]"))
+			},
+			wantErr: new(*goval.InvalidOperationError),
+		},
+		{
+			name: "delete where",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members[*]"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return v.Age > 30
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = []collMember{want.Members[0]}
+			},
+		},
+		{
+			name: "delete where filter",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse(`Members[?(@.Name != "Bob")]`), func(v collMember, pathInfo goval.PathInfo) bool {
+					return pathInfo.Keys[0] != 0
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = []collMember{want.Members[0], want.Members[1]}
+			},
+		},
+		{
+			name: "delete where range",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members[0:1]"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[1:]
+			},
+		},
+		{
+			name: "delete where index",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members[2]"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[:2]
+			},
+		},
+		{
+			name: "delete where negative index",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members[-3]"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[1:]
+			},
+		},
+		{
+			name: "delete where union",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members[0,2,2,9]"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return v.Age > 30
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[:2]
+			},
+		},
+		{
+			name: "delete where map key",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Labels[team]"), func(v string, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				delete(want.Labels, "team")
+			},
+		},
+		{
+			name: "delete where range of map",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Labels[0:1]"), func(v string, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			wantErr: new(*goval.InvalidOperationError),
+		},
+		{
+			name: "delete where field",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Members"), func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			wantErr: new(*goval.InvalidOperationError),
+		},
+		{
+			name: "append to missing field in strict mode",
+			op: func(target *collTeam) error {
+				return goval.Append(target, parse("Missing"), []string{"a"}, goval.Strict())
+			},
+			wantErr: new(*goval.FieldNotFoundError),
+		},
+		{
+			name: "insert to missing field in strict mode",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, parse("Missing[0]"), "a", goval.Strict())
+			},
+			wantErr: new(*goval.FieldNotFoundError),
+		},
+		{
+			name: "insert with builder path",
+			op: func(target *collTeam) error {
+				return goval.Insert(target, goval.Field("Labels").Key("k"), "v")
+			},
+			want: func(want *collTeam) {
+				want.Labels["k"] = "v"
+			},
+		},
+		{
+			name: "delete with builder path",
+			op: func(target *collTeam) error {
+				return goval.Delete(target, goval.Field("Members").Index(0))
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[1:]
+			},
+		},
+		{
+			name: "delete where with builder path",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, goval.Field("Members").Index(-1), func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[:2]
+			},
+		},
+		{
+			name: "delete where with text path",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, goval.TextPath{Path: parse("Members[?(@.Age > 30)]")}, func(v collMember, pathInfo goval.PathInfo) bool {
+					return true
+				})
+			},
+			want: func(want *collTeam) {
+				want.Members = want.Members[:1]
+			},
+		},
+		{
+			name: "delete where map",
+			op: func(target *collTeam) error {
+				return goval.DeleteWhere(target, parse("Labels[*]"), func(v string, pathInfo goval.PathInfo) bool {
+					return v == "prod"
+				})
+			},
+			want: func(want *collTeam) {
+				delete(want.Labels, "env")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newCollTeam()
+			err := tt.op(target)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			want := newCollTeam()
+			tt.want(want)
+			if !reflect.DeepEqual(target, want) {
+				t.Errorf("got %+v, want %+v", target, want)
+			}
+		})
+	}
+}
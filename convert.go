@@ -0,0 +1,121 @@
+package goval
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// errNotConvertible is returned by convert when the types are not convertible, reported as TypeMismatchError.
+var errNotConvertible = errors.New("not convertible")
+
+// convert converts the value to the type t. it supports
+//   - numeric conversion with overflow checks. e.g. int to int32, float64 to int if integral
+//   - named type conversion of the same kind. e.g. string to `type Status string`
+//   - pointer wrapping and unwrapping. e.g. string to *string, *int to int
+//   - strings and byte slices to the types implementing encoding.TextUnmarshaler. e.g. string to time.Time
+func convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(t), nil
+	case v.Type().AssignableTo(t):
+		return v, nil
+	case v.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		return convert(v.Elem(), t)
+	case isText(v.Type()) && reflect.PointerTo(t).Implements(textUnmarshalerType):
+		text := []byte(v.String())
+		if v.Kind() != reflect.String {
+			text = v.Bytes()
+		}
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	case t.Kind() == reflect.Ptr:
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Zero(t), nil
+			}
+			v = v.Elem()
+		}
+		ev, err := convert(v, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(ev)
+		return p, nil
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		return convert(v.Elem(), t)
+	case isNumber(v.Kind()) && isNumber(t.Kind()):
+		if err := checkOverflow(v, t); err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Convert(t), nil
+	case v.Kind() == t.Kind() && v.Type().ConvertibleTo(t):
+		return v.Convert(t), nil
+	}
+	return reflect.Value{}, errNotConvertible
+}
+
+// isText reports whether the type is a string or a byte slice, including the named types.
+func isText(t reflect.Type) bool {
+	return t.Kind() == reflect.String || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func isNumber(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Float64 && k != reflect.Uintptr
+}
+
+// checkOverflow returns an error if the number can not be represented by the type t.
+func checkOverflow(v reflect.Value, t reflect.Type) error {
+	zero := reflect.Zero(t)
+	var overflow bool
+	switch {
+	case v.CanInt():
+		n := v.Int()
+		switch {
+		case zero.CanInt():
+			overflow = zero.OverflowInt(n)
+		case zero.CanUint():
+			overflow = n < 0 || zero.OverflowUint(uint64(n))
+		}
+	case v.CanUint():
+		n := v.Uint()
+		switch {
+		case zero.CanInt():
+			overflow = n > math.MaxInt64 || zero.OverflowInt(int64(n))
+		case zero.CanUint():
+			overflow = zero.OverflowUint(n)
+		}
+	case v.CanFloat():
+		f := v.Float()
+		switch {
+		case zero.CanInt():
+			if f != math.Trunc(f) {
+				return fmt.Errorf("%v is not an integer", f)
+			}
+			overflow = f < math.MinInt64 || f >= math.MaxInt64 || zero.OverflowInt(int64(f))
+		case zero.CanUint():
+			if f != math.Trunc(f) {
+				return fmt.Errorf("%v is not an integer", f)
+			}
+			overflow = f < 0 || f >= math.MaxUint64 || zero.OverflowUint(uint64(f))
+		case zero.CanFloat():
+			overflow = !math.IsInf(f, 0) && zero.OverflowFloat(f)
+		}
+	}
+	if overflow {
+		return fmt.Errorf("%v overflows %v", v, t)
+	}
+	return nil
+}
@@ -1,7 +1,13 @@
 package goval
 
 import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 )
 
 // funcEach a callback func given to each function.
@@ -10,94 +16,747 @@ import (
 // owner: Structure owning the value pointer.
 type funcEach func(v any, pathInfo PathInfo)
 
+// funcEachE a callback func returning an error, which stops the traversal.
+type funcEachE func(v any, pathInfo PathInfo) error
+
 // Each executes the given function once for each field specified in the path.
-func Each(target any, path Path, fn funcEach) {
+func Each(target any, path Path, fn funcEach, opts ...Option) {
+	if err := TryEach(target, path, fn, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// TryEach is like Each but returns an error instead of panicking.
+func TryEach(target any, path Path, fn funcEach, opts ...Option) error {
+	return tryEach(target, path, func(v any, pathInfo PathInfo) error {
+		fn(v, pathInfo)
+		return nil
+	}, opts)
+}
+
+// Stop is returned by the callback of EachE and EachContext to stop the traversal without an error.
+var Stop = errors.New("stop")
+
+// EachE executes the given function once for each field specified in the path, until the function returns an error.
+// The error is returned, except Stop which stops the traversal successfully.
+func EachE(target any, path Path, fn funcEachE, opts ...Option) error {
+	return EachContext(context.Background(), target, path, fn, opts...)
+}
+
+// EachContext is like EachE but checks the cancellation of the context before each field,
+// and returns the error of the context when cancelled.
+func EachContext(ctx context.Context, target any, path Path, fn funcEachE, opts ...Option) error {
+	err := tryEachContext(ctx, target, path, fn, opts, false)
+	if errors.Is(err, Stop) {
+		return nil
+	}
+	return err
+}
+
+func tryEach(target any, path Path, fn funcEachE, opts []Option) error {
+	return tryEachContext(context.Background(), target, path, fn, opts, false)
+}
+
+// tryWrite is like tryEach but for the functions writing the fields, which create the missing values with WithCreate.
+func tryWrite(target any, path Path, fn funcEachE, opts []Option) error {
+	return tryEachContext(context.Background(), target, path, fn, opts, true)
+}
+
+func tryEachContext(ctx context.Context, target any, path Path, fn funcEachE, opts []Option, write bool) error {
+	var paths []Path
+	if path != nil {
+		paths = path.Split()
+	}
 	refTarget := reflect.ValueOf(target)
+	if refTarget.Kind() != reflect.Ptr {
+		var first Path
+		if len(paths) > 0 {
+			first = paths[0]
+		}
+		return &InvalidTargetError{Path: first, Type: reflect.TypeOf(target)}
+	}
+	w := &walker{
+		opts:  newOptions(opts),
+		fn:    fn,
+		ctx:   ctx,
+		write: write,
+	}
 	pathInfo := PathInfo{
 		RequirePath: path,
 	}
-	each(refTarget, path.Split(), pathInfo, fn)
+	return w.each(refTarget, paths, pathInfo)
+}
+
+// walker walks the target along the path with the options.
+type walker struct {
+	opts  options
+	fn    funcEachE
+	ctx   context.Context // nil for the walkers evaluating filters only.
+	write bool            // true for the walkers writing the fields, which may create the missing values.
+}
+
+// call executes the function with the field value, if the context is not cancelled.
+func (w *walker) call(v any, pathInfo PathInfo) error {
+	if err := w.ctxErr(); err != nil {
+		return err
+	}
+	return w.fn(v, pathInfo)
+}
+
+// ctxErr returns the error of the context, to stop walking the elements once it is cancelled.
+func (w *walker) ctxErr() error {
+	if w.ctx == nil {
+		return nil
+	}
+	return w.ctx.Err()
+}
+
+// checkDepth returns MaxDepthError if the next segment exceeds the max depth.
+func (w *walker) checkDepth(pathInfo PathInfo) error {
+	if w.opts.maxDepth > 0 && pathInfo.Depth >= w.opts.maxDepth {
+		return &MaxDepthError{Path: pathInfo.ResolvedPath, MaxDepth: w.opts.maxDepth}
+	}
+	return nil
+}
+
+// create reports whether the missing values on the path can be created.
+// reads never create the values, so that they do not change the target.
+func (w *walker) create(pathInfo PathInfo) bool {
+	return w.write && w.opts.create && !pathInfo.readOnly
+}
+
+// skip returns the error in strict mode, otherwise nil to skip the path silently.
+func (w *walker) skip(err error) error {
+	if w.opts.strict {
+		return err
+	}
+	return nil
 }
 
 // each executes the given function once for each field specified in the path.
 //
 // refTargetAddr: 参照のValueである必要がある
-func each(target reflect.Value, paths []Path, pathInfo PathInfo, fn funcEach) {
+func (w *walker) each(target reflect.Value, paths []Path, pathInfo PathInfo) error {
 	if len(paths) == 0 {
-		return
+		return nil
 	}
+	current := paths[0]
 	switch target.Kind() {
 	case reflect.Ptr, reflect.Array, reflect.Slice:
 	default:
-		panic("invalid target, must be pointer,array,slice")
+		return &InvalidTargetError{Path: current, Type: target.Type()}
 	}
 	if target.IsNil() {
-		return
+		return w.skip(&NilPointerError{Path: current.Parent(), Type: target.Type()})
+	}
+	if err := w.checkDepth(pathInfo); err != nil {
+		return err
+	}
+	if p, ok := current.(*pathRecursive); ok {
+		return w.eachRecursive(target, p, paths, pathInfo, visitSet{})
 	}
 	pathInfo.Owner = target.Interface()
+	pathInfo.mapValue = reflect.Value{}
+	pathInfo.mapKey = reflect.Value{}
 
-	current := paths[0]
-	fv := elem(target).FieldByName(current.Name())
-	if !fv.IsValid() {
-		return
+	ev := elem(target)
+	if ev.Kind() != reflect.Struct {
+		return &InvalidTargetError{Path: current, Type: target.Type()}
+	}
+	if _, ok := current.(*pathFieldAll); ok {
+		// all fields match, expand to field paths and execute.
+		t := ev.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if len(paths) > 1 && !hasFields(t.Field(i).Type) {
+				continue
+			}
+			pf := &path{
+				parent: current.Parent(),
+				name:   w.opts.fieldName(t.Field(i)),
+				ptype:  PathTypeValue,
+			}
+			newPaths := make([]Path, 0, len(paths))
+			newPaths = append(newPaths, pf)
+			newPaths = append(newPaths, paths[1:]...)
+			if err := w.each(target, newPaths, pathInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	sf, ok := w.opts.fieldByName(ev.Type(), current.Name())
+	if !ok {
+		// the field may be promoted through the embedded interface.
+		if iv, ok := w.opts.embeddedInterface(ev, current.Name()); ok {
+			nextTarget, err := w.target(iv, current, &pathInfo)
+			if err != nil || !nextTarget.IsValid() {
+				return err
+			}
+			return w.each(nextTarget, paths, pathInfo)
+		}
+		return w.skip(&FieldNotFoundError{Path: current, Type: ev.Type()})
 	}
+	fv, ok := fieldByIndex(ev, sf.Index, w.create(pathInfo))
+	if !ok {
+		return w.skip(&NilPointerError{Path: current, Type: fv.Type()})
+	}
+	if !fv.CanInterface() { // unexported field, or promoted through the unexported embedded struct.
+		if w.opts.access == ExportedOnly {
+			return &UnexportedFieldError{Path: current, Type: fv.Type()}
+		}
+		fv = exposeUnexported(fv)
+		pathInfo.readOnly = pathInfo.readOnly || w.opts.access != UnsafeWriteUnexported
+	}
+	pathInfo.FieldName = sf.Name
+	pathInfo.StructField = sf
 	pathInfo.fieldValue = fv
-	field := fieldValueAny(fv)
+	field := w.opts.value(fv, pathInfo.readOnly)
 
 	switch p := current.(type) {
+	case *pathMapKey:
+		if fv.Kind() != reflect.Map {
+			return w.skip(&KeyNotFoundError{Path: current, Key: p.key})
+		}
+		return w.eachMapKey(fv, p.key, current, paths[1:], pathInfo)
 	case *pathList:
-		if p.index >= fv.Len() {
-			return
+		if fv.Kind() == reflect.Map {
+			return w.eachMapKey(fv, strconv.Itoa(p.index), current, paths[1:], pathInfo)
+		}
+		index := p.index
+		if index < 0 { // negative index from the end. e.g. foo[-1]
+			index += fv.Len()
+			if index < 0 {
+				return w.skip(&IndexOutOfRangeError{Path: current, Index: p.index, Len: fv.Len()})
+			}
+		}
+		if index >= fv.Len() {
+			if !w.create(pathInfo) || fv.Kind() != reflect.Slice || !fv.CanSet() {
+				return w.skip(&IndexOutOfRangeError{Path: current, Index: p.index, Len: fv.Len()})
+			}
+			n := index + 1 - fv.Len()
+			fv.Set(reflect.AppendSlice(fv, reflect.MakeSlice(fv.Type(), n, n)))
 		}
-		fv = fv.Index(p.index)
+		fv = fv.Index(index)
 		if !fv.IsValid() {
-			return
+			return nil
 		}
 		pathInfo.fieldValue = fv
-		field = fieldValueAny(fv)
+		pathInfo.resolve(&pathList{
+			path: path{
+				parent: pathInfo.ResolvedPath,
+				name:   current.Name(),
+				ptype:  PathTypeValue,
+			},
+			index: index,
+		}, index)
+		field = w.opts.value(fv, pathInfo.readOnly)
 	case *pathListAll:
+		if fv.Kind() == reflect.Map {
+			for _, key := range sortedMapKeys(fv) {
+				if err := w.eachMapEntry(fv, key, current, paths[1:], pathInfo); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
 		// all index match, expand to pathLists and execute.
 		for i := 0; i < fv.Len(); i++ {
-			pl := &pathList{
-				path: path{
-					parent: current.Parent(),
-					name:   current.Name(),
-					ptype:  PathTypeValue,
-				},
-				index: i,
+			if err := w.eachIndex(target, current, i, paths, pathInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *pathListRange:
+		if fv.Kind() == reflect.Map {
+			return w.skip(&InvalidOperationError{Op: "slice", Path: current, Type: fv.Type()})
+		}
+		for _, i := range p.indexes(fv.Len()) {
+			if err := w.eachIndex(target, current, i, paths, pathInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *pathListUnion:
+		for _, i := range p.indexes {
+			if fv.Kind() == reflect.Map {
+				if err := w.eachMapKey(fv, strconv.Itoa(i), current, paths[1:], pathInfo); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := w.eachIndex(target, current, i, paths, pathInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *pathFilter:
+		if fv.Kind() == reflect.Map {
+			for _, key := range sortedMapKeys(fv) {
+				if err := w.ctxErr(); err != nil {
+					return err
+				}
+				ok, err := p.filter.eval(w, fv.MapIndex(key))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+				if err := w.eachMapEntry(fv, key, current, paths[1:], pathInfo); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		// matched index, expand to pathLists and execute.
+		for i := 0; i < fv.Len(); i++ {
+			if err := w.ctxErr(); err != nil {
+				return err
+			}
+			ok, err := p.filter.eval(w, fv.Index(i))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if err := w.eachIndex(target, current, i, paths, pathInfo); err != nil {
+				return err
 			}
-			newPaths := make([]Path, 0, len(paths))
-			newPaths = append(newPaths, pl)
-			newPaths = append(newPaths, paths[1:]...)
-			each(target, newPaths, pathInfo, fn)
 		}
-		return
+		return nil
+	default:
+		pathInfo.resolve(&path{
+			parent: pathInfo.ResolvedPath,
+			name:   current.Name(),
+			ptype:  PathTypeValue,
+		})
 	}
 
 	// execute function, when last path element
 	if len(paths) == 1 && current.Type() == PathTypeValue {
-		fn(field, pathInfo)
-		return
+		return w.call(field, pathInfo)
+	}
+
+	nextTarget, err := w.target(fv, current, &pathInfo)
+	if err != nil || !nextTarget.IsValid() {
+		return err
 	}
+	return w.each(nextTarget, paths[1:], pathInfo)
+}
 
-	var nextTarget reflect.Value
+// target returns the pointer to continue the path from the field value.
+// an invalid value is returned when the nil pointer is skipped.
+//
+// values held by interfaces are not addressable, so structs are copied and written back to the interface on set.
+func (w *walker) target(fv reflect.Value, current Path, pathInfo *PathInfo) (reflect.Value, error) {
 	switch fv.Kind() {
 	case reflect.Ptr:
 		if fv.IsNil() {
-			return
+			if !w.create(*pathInfo) || !fv.CanSet() {
+				return reflect.Value{}, w.skip(&NilPointerError{Path: current, Type: fv.Type()})
+			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return fv, nil
+	case reflect.Interface:
+		if fv.IsNil() {
+			return reflect.Value{}, w.skip(&NilPointerError{Path: current, Type: fv.Type()})
+		}
+		ev := fv.Elem()
+		if ev.Kind() == reflect.Ptr {
+			if ev.IsNil() {
+				return reflect.Value{}, w.skip(&NilPointerError{Path: current, Type: ev.Type()})
+			}
+			return ev, nil
 		}
-		nextTarget = fv
+		if !ev.CanInterface() { // held by the unexported field
+			return reflect.Value{}, w.skip(&InvalidTargetError{Path: current, Type: fv.Type()})
+		}
+		cp := reflect.New(ev.Type())
+		cp.Elem().Set(ev)
+		if fv.CanSet() {
+			pathInfo.writeBack = append(pathInfo.writeBack[:len(pathInfo.writeBack):len(pathInfo.writeBack)], interfaceWriteBack(fv, cp))
+		}
+		return cp, nil
+	}
+	return fv.Addr(), nil
+}
+
+// interfaceWriteBack returns a function writing the copied value back to the interface.
+func interfaceWriteBack(iv reflect.Value, cp reflect.Value) func() {
+	return func() {
+		iv.Set(cp.Elem())
+	}
+}
+
+// hasFields reports whether the path can continue to the fields of the type.
+func hasFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Interface
+}
+
+// visit is a pointer, slice or map visited by the recursive descent.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type visitSet map[visit]bool
+
+// add marks the value visited, and reports whether it was not visited yet.
+func (s visitSet) add(v reflect.Value) bool {
+	k := visit{ptr: v.Pointer(), typ: v.Type()}
+	if s[k] {
+		return false
+	}
+	s[k] = true
+	return true
+}
+
+// eachRecursive searches the field of the recursive path segment in the target struct and all nested values.
+func (w *walker) eachRecursive(target reflect.Value, current *pathRecursive, paths []Path, pathInfo PathInfo, visited visitSet) error {
+	if target.IsNil() {
+		return nil
+	}
+	if !visited.add(target) {
+		return nil
+	}
+	if err := w.checkDepth(pathInfo); err != nil {
+		return err
+	}
+
+	ev := elem(target)
+	if ev.Kind() != reflect.Struct {
+		return nil
+	}
+	_, all := current.Path.(*pathFieldAll)
+	if _, ok := w.opts.fieldByName(ev.Type(), current.Name()); ok || all {
+		newPaths := make([]Path, 0, len(paths))
+		newPaths = append(newPaths, current.Path)
+		newPaths = append(newPaths, paths[1:]...)
+		if err := w.each(target, newPaths, pathInfo); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < ev.NumField(); i++ {
+		if !ev.Type().Field(i).IsExported() {
+			continue
+		}
+		info := pathInfo
+		info.resolve(&path{
+			parent: pathInfo.ResolvedPath,
+			name:   w.opts.fieldName(ev.Type().Field(i)),
+			ptype:  PathTypeValue,
+		})
+		if err := w.descend(ev.Field(i), current, paths, info, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// descend searches the recursive path segment in the nested values of v.
+func (w *walker) descend(v reflect.Value, current *pathRecursive, paths []Path, pathInfo PathInfo, visited visitSet) error {
+	if err := w.ctxErr(); err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if ev := v.Elem(); ev.Kind() == reflect.Struct && ev.CanInterface() {
+			cp := reflect.New(ev.Type())
+			cp.Elem().Set(ev)
+			if v.CanSet() {
+				pathInfo.writeBack = append(pathInfo.writeBack[:len(pathInfo.writeBack):len(pathInfo.writeBack)], interfaceWriteBack(v, cp))
+			}
+			return w.eachRecursive(cp, current, paths, pathInfo, visited)
+		}
+		return w.descend(v.Elem(), current, paths, pathInfo, visited)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			return w.eachRecursive(v, current, paths, pathInfo, visited)
+		}
+		if !visited.add(v) {
+			return nil
+		}
+		return w.descend(v.Elem(), current, paths, pathInfo, visited)
+	case reflect.Struct:
+		if !v.CanAddr() {
+			return nil
+		}
+		return w.eachRecursive(v.Addr(), current, paths, pathInfo, visited)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || !visited.add(v)) {
+			return nil
+		}
+		seg := pathInfo.ResolvedPath
+		for i := 0; i < v.Len(); i++ {
+			info := pathInfo
+			info.resolveKey(indexSegment(seg, i), i)
+			if err := w.descend(v.Index(i), current, paths, info, visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || !visited.add(v) {
+			return nil
+		}
+		seg := pathInfo.ResolvedPath
+		for _, key := range sortedMapKeys(v) {
+			info := pathInfo
+			info.resolveKey(keySegment(seg, key), keyInterface(key))
+			mv := v.MapIndex(key)
+			if mv.Kind() == reflect.Interface && !mv.IsNil() {
+				mv = mv.Elem()
+			}
+			if mv.Kind() != reflect.Struct {
+				if err := w.descend(mv, current, paths, info, visited); err != nil {
+					return err
+				}
+				continue
+			}
+			cp := reflect.New(mv.Type())
+			cp.Elem().Set(mv)
+			info.writeBack = append(pathInfo.writeBack[:len(pathInfo.writeBack):len(pathInfo.writeBack)], mapWriteBack(v, key, cp))
+			if err := w.eachRecursive(cp, current, paths, info, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexSegment returns the segment selecting the element of the collection segment by the index.
+func indexSegment(seg Path, index int) Path {
+	return &pathList{
+		path: path{
+			parent: seg.Parent(),
+			name:   seg.Name(),
+			ptype:  PathTypeValue,
+		},
+		index: index,
+	}
+}
+
+// keySegment returns the segment selecting the entry of the map segment by the key.
+func keySegment(seg Path, key reflect.Value) Path {
+	return &pathMapKey{
+		path: path{
+			parent: seg.Parent(),
+			name:   seg.Name(),
+			ptype:  PathTypeValue,
+		},
+		key: keyString(key),
+	}
+}
+
+// eachIndex executes the given function for the collection path expanded to the index.
+func (w *walker) eachIndex(target reflect.Value, current Path, index int, paths []Path, pathInfo PathInfo) error {
+	if err := w.ctxErr(); err != nil {
+		return err
+	}
+	pl := &pathList{
+		path: path{
+			parent: current.Parent(),
+			name:   current.Name(),
+			ptype:  PathTypeValue,
+		},
+		index: index,
+	}
+	newPaths := make([]Path, 0, len(paths))
+	newPaths = append(newPaths, pl)
+	newPaths = append(newPaths, paths[1:]...)
+	return w.each(target, newPaths, pathInfo)
+}
+
+// eachMapKey executes the given function for the map entry specified by the key string.
+func (w *walker) eachMapKey(m reflect.Value, keyStr string, current Path, paths []Path, pathInfo PathInfo) error {
+	key, err := mapKey(m.Type().Key(), keyStr)
+	if err != nil {
+		return w.skip(&TypeMismatchError{Path: current, Want: m.Type().Key(), Got: reflect.TypeOf(keyStr)})
+	}
+	if m.IsNil() && w.create(pathInfo) && m.CanSet() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	return w.eachMapEntry(m, key, current, paths, pathInfo)
+}
+
+// eachMapEntry executes the given function for the map entry specified by the key.
+//
+// map entries are not addressable, so struct values are copied and written back to the map on set.
+func (w *walker) eachMapEntry(m reflect.Value, key reflect.Value, current Path, paths []Path, pathInfo PathInfo) error {
+	if err := w.ctxErr(); err != nil {
+		return err
+	}
+	v := m.MapIndex(key)
+	if !v.IsValid() {
+		if !w.create(pathInfo) || m.IsNil() || !m.CanInterface() {
+			return w.skip(&KeyNotFoundError{Path: current, Key: keyString(key)})
+		}
+		v = reflect.Zero(m.Type().Elem())
+	}
+	pathInfo.resolve(&pathMapKey{
+		path: path{
+			parent: pathInfo.ResolvedPath,
+			name:   current.Name(),
+			ptype:  PathTypeValue,
+		},
+		key: keyString(key),
+	}, keyInterface(key))
+	if len(paths) == 0 {
+		pathInfo.fieldValue = v
+		pathInfo.mapValue = m
+		pathInfo.mapKey = key
+		return w.call(w.opts.value(v, pathInfo.readOnly), pathInfo)
+	}
+
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return w.skip(&NilPointerError{Path: current, Type: v.Type()})
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if !w.create(pathInfo) || !m.CanInterface() {
+				return w.skip(&NilPointerError{Path: current, Type: v.Type()})
+			}
+			v = reflect.New(v.Type().Elem())
+			m.SetMapIndex(key, v)
+		}
+		return w.each(v, paths, pathInfo)
+	case reflect.Struct:
+		cp := reflect.New(v.Type())
+		cp.Elem().Set(v)
+		pathInfo.writeBack = append(pathInfo.writeBack[:len(pathInfo.writeBack):len(pathInfo.writeBack)], mapWriteBack(m, key, cp))
+		return w.each(cp, paths, pathInfo)
+	}
+	return &InvalidTargetError{Path: paths[0], Type: v.Type()}
+}
+
+// mapWriteBack returns a function writing the copied map entry back to the map.
+func mapWriteBack(m reflect.Value, key reflect.Value, cp reflect.Value) func() {
+	return func() {
+		m.SetMapIndex(key, cp.Elem())
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// mapKey convert the key string of path to the map key type.
+func mapKey(t reflect.Type, s string) (reflect.Value, error) {
+	key := reflect.New(t)
+	if key.Type().Implements(textUnmarshalerType) {
+		if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return key.Elem(), nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		key.Elem().SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		key.Elem().SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		key.Elem().SetUint(n)
 	default:
-		nextTarget = fv.Addr()
+		return reflect.Value{}, errors.New("unsupported map key type: " + t.String())
 	}
-	each(nextTarget, paths[1:], pathInfo, fn)
+	return key.Elem(), nil
 }
 
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// keyString returns the map key in the form of path string.
+func keyString(key reflect.Value) string {
+	if key.Type().Implements(textMarshalerType) && key.CanInterface() {
+		if b, err := key.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	switch key.Kind() {
+	case reflect.String:
+		return key.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	}
+	return fmt.Sprint(key)
+}
+
+// keyInterface returns the map key as interface, even if it is obtained from an unexported field.
+func keyInterface(key reflect.Value) any {
+	if key.CanInterface() {
+		return key.Interface()
+	}
+	return fieldValueAny(key)
+}
+
+// sortedMapKeys returns the map keys in a stable order.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch a.Kind() {
+		case reflect.String:
+			return a.String() < b.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		default:
+			return fmt.Sprint(a) < fmt.Sprint(b)
+		}
+	})
+	return keys
+}
+
+// fieldValue returns the value of the field in its declared type, dereferencing the pointers and interfaces.
+func fieldValue(fv reflect.Value) any {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if !fv.IsValid() || !fv.CanInterface() {
+		return fieldValueAny(fv)
+	}
+	return fv.Interface()
+}
+
+// fieldValueAny returns the value of the field, converting numbers, strings and bools to the basic types of their kinds.
 func fieldValueAny(fv reflect.Value) any {
 	fv = reflect.Indirect(fv)
 	switch {
 	case !fv.IsValid():
 		return nil
+	case fv.Kind() == reflect.Interface:
+		if fv.IsNil() {
+			return nil
+		}
+		return fieldValueAny(fv.Elem())
 	case fv.CanInt():
 		return fieldValueInt(fv)
 	case fv.CanUint():
@@ -108,14 +767,38 @@ func fieldValueAny(fv reflect.Value) any {
 		return fieldValueComplex(fv)
 	case fv.Kind() == reflect.String:
 		return fv.String()
-	case fv.CanAddr():
-		return fv.Addr()
+	case fv.Kind() == reflect.Bool:
+		return fv.Bool()
 	case fv.CanInterface():
 		return fv.Interface()
 	}
 	return nil
 }
 
+// fieldValuePtr returns the pointer to the value of the field.
+// pointer fields are returned as is, and values not addressable, such as map entries, are copied.
+func fieldValuePtr(fv reflect.Value) any {
+	for fv.Kind() == reflect.Ptr && fv.Elem().Kind() == reflect.Ptr {
+		fv = fv.Elem()
+	}
+	switch {
+	case !fv.IsValid() || !fv.CanInterface():
+		return nil
+	case fv.Kind() == reflect.Interface:
+		if fv.IsNil() {
+			return nil
+		}
+		return fieldValuePtr(fv.Elem())
+	case fv.Kind() == reflect.Ptr:
+		return fv.Interface()
+	case fv.CanAddr():
+		return fv.Addr().Interface()
+	}
+	cp := reflect.New(fv.Type())
+	cp.Elem().Set(fv)
+	return cp.Interface()
+}
+
 func fieldValueInt(fv reflect.Value) any {
 	n := fv.Int()
 	switch fv.Kind() {
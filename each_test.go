@@ -1,9 +1,13 @@
 package goval_test
 
 import (
+	"context"
+	"errors"
 	"github.com/tadjp/goval"
 	"math"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -229,6 +233,137 @@ func TestEach(t *testing.T) {
 			}
 			return tt
 		}),
+		defaultTest(func(tt test) test {
+			tt.name = "map key field"
+			type s struct {
+				Labels map[string]string
+			}
+			target := s{
+				Labels: map[string]string{
+					"env":    "prod",
+					"my.key": "foo",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = "Labels[env]"
+			tt.wants = []want{
+				{v: "prod", pathInfo: goval.PathInfo{Owner: &target}},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "quoted map key field"
+			type s struct {
+				Labels map[string]string
+			}
+			target := s{
+				Labels: map[string]string{
+					"env":    "prod",
+					"my.key": "foo",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = `Labels["my.key"]`
+			tt.wants = []want{
+				{v: "foo", pathInfo: goval.PathInfo{Owner: &target}},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "missing map key field"
+			type s struct {
+				Labels map[string]string
+			}
+			target := s{
+				Labels: map[string]string{
+					"env": "prod",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = "Labels[region]"
+			tt.wants = nil
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "integer map key field"
+			type s struct {
+				Codes map[int]string
+			}
+			target := s{
+				Codes: map[int]string{
+					200: "OK",
+					404: "Not Found",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = "Codes[404]"
+			tt.wants = []want{
+				{v: "Not Found", pathInfo: goval.PathInfo{Owner: &target}},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "text unmarshaler map key field"
+			type s struct {
+				Hosts map[netip.Addr]string
+			}
+			target := s{
+				Hosts: map[netip.Addr]string{
+					netip.MustParseAddr("127.0.0.1"): "localhost",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = `Hosts["127.0.0.1"]`
+			tt.wants = []want{
+				{v: "localhost", pathInfo: goval.PathInfo{Owner: &target}},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "all map fields"
+			type s struct {
+				Labels map[string]string
+			}
+			target := s{
+				Labels: map[string]string{
+					"c": "3",
+					"a": "1",
+					"b": "2",
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = "Labels[*]"
+			tt.wants = []want{
+				{v: "1", pathInfo: goval.PathInfo{Owner: &target}},
+				{v: "2", pathInfo: goval.PathInfo{Owner: &target}},
+				{v: "3", pathInfo: goval.PathInfo{Owner: &target}},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "nested map struct fields"
+			type t struct {
+				Name string
+			}
+			type s struct {
+				Ts    map[string]t
+				PtrTs map[string]*t
+			}
+			target := s{
+				Ts: map[string]t{
+					"a": {Name: "Alice"},
+				},
+				PtrTs: map[string]*t{
+					"b": {Name: "Bob"},
+				},
+			}
+			tt.args.target = &target
+			tt.args.path = "PtrTs[b].Name"
+			tt.wants = []want{
+				{v: "Bob", pathInfo: goval.PathInfo{Owner: target.PtrTs["b"]}},
+			}
+			return tt
+		}),
 	}
 
 	s := struct {
@@ -415,3 +550,797 @@ func TestEach(t *testing.T) {
 		})
 	}
 }
+
+func TestTryEachStrict(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	type team struct {
+		Leader  *member
+		Members []member
+		Labels  map[string]string
+	}
+	type test struct {
+		name    string
+		path    string
+		wantErr any
+	}
+	target := &team{
+		Members: []member{{Name: "Alice"}},
+		Labels:  map[string]string{"env": "prod"},
+	}
+	tests := []test{
+		{name: "existing field", path: "Members[0].Name"},
+		{name: "missing field", path: "Members[0].Age", wantErr: new(*goval.FieldNotFoundError)},
+		{name: "index out of range", path: "Members[1].Name", wantErr: new(*goval.IndexOutOfRangeError)},
+		{name: "nil pointer", path: "Leader.Name", wantErr: new(*goval.NilPointerError)},
+		{name: "missing map key", path: "Labels[region]", wantErr: new(*goval.KeyNotFoundError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fn := func(v any, pathInfo goval.PathInfo) {}
+			if err := goval.TryEach(target, path, fn); err != nil {
+				t.Errorf("TryEach(%v) error = %v, want nil without strict mode", tt.path, err)
+			}
+			err = goval.TryEach(target, path, fn, goval.Strict())
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("TryEach(%v) error = %v", tt.path, err)
+				}
+				return
+			}
+			if !errors.As(err, tt.wantErr) {
+				t.Errorf("TryEach(%v) error = %v, want %T", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEachRecursive(t *testing.T) {
+	type credentials struct {
+		User     string
+		Password string
+	}
+	type database struct {
+		Credentials credentials
+		Replicas    []*database
+	}
+	type node struct {
+		Name     string
+		Password string
+		Parent   *node
+		Children []*node
+	}
+	type config struct {
+		Password  string
+		Database  *database
+		Services  map[string]credentials
+		Extra     any
+		Root      *node
+		Passwords []string
+	}
+
+	root := &node{Name: "root", Password: "r"}
+	child := &node{Name: "child", Password: "c", Parent: root}
+	root.Children = []*node{child}
+	target := &config{
+		Password: "p0",
+		Database: &database{
+			Credentials: credentials{User: "u1", Password: "p1"},
+			Replicas: []*database{
+				{Credentials: credentials{User: "u2", Password: "p2"}},
+				nil,
+			},
+		},
+		Services: map[string]credentials{
+			"b": {Password: "p4"},
+			"a": {Password: "p3"},
+		},
+		Extra:     &credentials{Password: "p5"},
+		Root:      root,
+		Passwords: []string{"x"},
+	}
+
+	type test struct {
+		name string
+		path string
+		want []any
+	}
+	tests := []test{
+		{name: "all nested fields", path: "..Password", want: []any{"p0", "p1", "p2", "p3", "p4", "p5", "r", "c"}},
+		{name: "nested fields under field", path: "Database..Password", want: []any{"p1", "p2"}},
+		{name: "nested struct fields", path: "..Credentials.User", want: []any{"u1", "u2"}},
+		{name: "nested collection fields", path: "..Passwords[*]", want: []any{"x"}},
+		{name: "no fields", path: "..Secret", want: []any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := goval.GetAll[any](target, path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("redact all nested fields", func(t *testing.T) {
+		path, _ := goval.Parse("..Password")
+		goval.Set(target, path, "***")
+		got := goval.GetAll[string](target, path)
+		want := []string{"***", "***", "***", "***", "***", "***", "***", "***"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Set(%v) = %v, want %v", path, got, want)
+		}
+	})
+}
+
+func TestEachCycle(t *testing.T) {
+	type node struct {
+		Name     string
+		Parent   *node
+		Children []*node
+		Extra    map[string]any
+		Items    []any
+	}
+	root := &node{Name: "root"}
+	child := &node{Name: "child", Parent: root}
+	root.Children = []*node{child}
+	root.Extra = map[string]any{}
+	root.Extra["self"] = root.Extra
+	root.Items = []any{nil}
+	root.Items[0] = root.Items
+
+	type test struct {
+		name    string
+		path    string
+		opts    []goval.Option
+		want    []any
+		wantErr bool
+	}
+	tests := []test{
+		{name: "self-referential pointers", path: "Children[*].Parent.Children[*].Name", want: []any{"child"}},
+		{name: "recursive descent over cycles", path: "..Name", want: []any{"root", "child"}},
+		{name: "within max depth", path: "Children[*].Parent.Name", opts: []goval.Option{goval.MaxDepth(3)}, want: []any{"root"}},
+		{name: "max depth exceeded", path: "Children[*].Parent.Children[*].Name", opts: []goval.Option{goval.MaxDepth(3)}, wantErr: true},
+		{name: "recursive descent max depth exceeded", path: "..Name", opts: []goval.Option{goval.MaxDepth(1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := goval.TryGetAll[any](root, path, tt.opts...)
+			if tt.wantErr {
+				var depthErr *goval.MaxDepthError
+				if !errors.As(err, &depthErr) {
+					t.Fatalf("error = %v, want *goval.MaxDepthError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEachE(t *testing.T) {
+	type team struct {
+		Members []string
+	}
+	target := &team{Members: []string{"a", "b", "c"}}
+	path, _ := goval.Parse("Members[*]")
+	errTest := errors.New("test")
+
+	type test struct {
+		name    string
+		fn      func(v any, pathInfo goval.PathInfo) error
+		want    []any
+		wantErr error
+	}
+	var got []any
+	tests := []test{
+		{
+			name: "all elements",
+			fn: func(v any, _ goval.PathInfo) error {
+				got = append(got, v)
+				return nil
+			},
+			want: []any{"a", "b", "c"},
+		},
+		{
+			name: "stop",
+			fn: func(v any, _ goval.PathInfo) error {
+				got = append(got, v)
+				if v == "b" {
+					return goval.Stop
+				}
+				return nil
+			},
+			want: []any{"a", "b"},
+		},
+		{
+			name: "error",
+			fn: func(v any, _ goval.PathInfo) error {
+				got = append(got, v)
+				return errTest
+			},
+			want:    []any{"a"},
+			wantErr: errTest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			err := goval.EachE(target, path, tt.fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EachE() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EachE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var got []any
+		err := goval.EachContext(ctx, target, path, func(v any, _ goval.PathInfo) error {
+			got = append(got, v)
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("EachContext() error = %v, want %v", err, context.Canceled)
+		}
+		if want := []any{"a"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("EachContext() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("cancelled without callbacks", func(t *testing.T) {
+		type item struct {
+			Name string
+		}
+		target := &struct {
+			Items  []item
+			Groups map[string]item
+		}{
+			Items:  []item{{Name: "a"}},
+			Groups: map[string]item{"dev": {Name: "b"}},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, s := range []string{"Items[*].Missing", "Groups[*].Missing", `Items[?(@.Name == "x")]`, "..Missing"} {
+			path, _ := goval.Parse(s)
+			err := goval.EachContext(ctx, target, path, func(_ any, _ goval.PathInfo) error {
+				t.Errorf("EachContext(%q) called the function", s)
+				return nil
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("EachContext(%q) error = %v, want %v", s, err, context.Canceled)
+			}
+		}
+	})
+}
+
+func TestEachValueMode(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	type team struct {
+		Leader  member
+		Members []*member
+		Groups  map[string]member
+		Tags    []string
+		Age     *int
+	}
+	age := 30
+	newTeam := func() *team {
+		return &team{
+			Leader:  member{Name: "Alice"},
+			Members: []*member{{Name: "Bob"}},
+			Groups:  map[string]member{"dev": {Name: "Carol"}},
+			Tags:    []string{"a"},
+			Age:     &age,
+		}
+	}
+
+	type test struct {
+		name string
+		path string
+		mode goval.ValueMode
+		want func(target *team) any
+	}
+	tests := []test{
+		{name: "struct by value", path: "Leader", want: func(target *team) any { return member{Name: "Alice"} }},
+		{name: "pointer by value", path: "Members[0]", want: func(target *team) any { return member{Name: "Bob"} }},
+		{name: "map entry by value", path: "Groups[dev]", want: func(target *team) any { return member{Name: "Carol"} }},
+		{name: "slice by value", path: "Tags", want: func(target *team) any { return []string{"a"} }},
+		{name: "struct by pointer", path: "Leader", mode: goval.ByPointer, want: func(target *team) any { return &target.Leader }},
+		{name: "pointer by pointer", path: "Members[0]", mode: goval.ByPointer, want: func(target *team) any { return target.Members[0] }},
+		{name: "map entry by pointer", path: "Groups[dev]", mode: goval.ByPointer, want: func(target *team) any { return &member{Name: "Carol"} }},
+		{name: "scalar by pointer", path: "Leader.Name", mode: goval.ByPointer, want: func(target *team) any { return &target.Leader.Name }},
+		{name: "scalar pointer by pointer", path: "Age", mode: goval.ByPointer, want: func(target *team) any { return target.Age }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := newTeam()
+			got := goval.GetAll[any](target, path, goval.WithValueMode(tt.mode))
+			want := []any{tt.want(target)}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, want)
+			}
+		})
+	}
+
+	t.Run("get struct values", func(t *testing.T) {
+		path, _ := goval.Parse("Members[0]")
+		got := goval.GetAll[member](newTeam(), path)
+		if want := []member{{Name: "Bob"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetAll() = %v, want %v", got, want)
+		}
+		ptrs := goval.GetAll[*member](newTeam(), path, goval.WithValueMode(goval.ByPointer))
+		if len(ptrs) != 1 || ptrs[0].Name != "Bob" {
+			t.Errorf("GetAll() = %v, want [Bob]", ptrs)
+		}
+	})
+	t.Run("raw value", func(t *testing.T) {
+		path, _ := goval.Parse("Leader")
+		got := goval.GetAll[reflect.Value](newTeam(), path, goval.WithValueMode(goval.RawValue))
+		if len(got) != 1 || got[0].Type() != reflect.TypeOf(member{}) {
+			t.Errorf("GetAll() = %v, want [reflect.Value of member]", got)
+		}
+	})
+	t.Run("set by value", func(t *testing.T) {
+		target := newTeam()
+		path, _ := goval.Parse("Groups[dev]")
+		goval.SetFunc(target, path, func(v member, _ goval.PathInfo) member {
+			v.Name += "!"
+			return v
+		})
+		if got := target.Groups["dev"].Name; got != "Carol!" {
+			t.Errorf("Groups[dev].Name = %q, want Carol!", got)
+		}
+	})
+	t.Run("set by pointer", func(t *testing.T) {
+		target := newTeam()
+		for _, s := range []string{"Leader", "Groups[dev]"} {
+			path, _ := goval.Parse(s)
+			goval.SetFunc(target, path, func(v *member, _ goval.PathInfo) *member {
+				v.Name += "!"
+				return v
+			}, goval.WithValueMode(goval.ByPointer))
+		}
+		if target.Leader.Name != "Alice!" || target.Groups["dev"].Name != "Carol!" {
+			t.Errorf("SetFunc() = %+v", target)
+		}
+	})
+	t.Run("set raw value", func(t *testing.T) {
+		target := newTeam()
+		path, _ := goval.Parse("Leader.Name")
+		goval.SetFunc(target, path, func(v reflect.Value, _ goval.PathInfo) reflect.Value {
+			return reflect.ValueOf(v.String() + "!")
+		}, goval.WithValueMode(goval.RawValue))
+		if target.Leader.Name != "Alice!" {
+			t.Errorf("Leader.Name = %q, want Alice!", target.Leader.Name)
+		}
+	})
+}
+
+func TestEachFieldAll(t *testing.T) {
+	type feature struct {
+		Enabled bool
+	}
+	type settings struct {
+		Debug  bool
+		Cache  feature
+		Search *feature
+		Beta   *feature
+		secret feature
+	}
+	type config struct {
+		Settings settings
+	}
+	target := &config{
+		Settings: settings{
+			Debug:  true,
+			Cache:  feature{Enabled: true},
+			Search: &feature{Enabled: false},
+		},
+	}
+
+	type test struct {
+		name       string
+		path       string
+		want       []any
+		wantFields []string
+	}
+	tests := []test{
+		{
+			name:       "all fields",
+			path:       "Settings.*",
+			want:       []any{true, feature{Enabled: true}, feature{Enabled: false}, nil},
+			wantFields: []string{"Debug", "Cache", "Search", "Beta"},
+		},
+		{
+			name:       "nested fields of all fields",
+			path:       "Settings.*.Enabled",
+			want:       []any{true, false},
+			wantFields: []string{"Enabled", "Enabled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []any
+			var gotFields []string
+			goval.Each(target, path, func(v any, pathInfo goval.PathInfo) {
+				got = append(got, v)
+				gotFields = append(gotFields, pathInfo.FieldName)
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Each(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("Each(%v) FieldName = %v, want %v", tt.path, gotFields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestEachSlice(t *testing.T) {
+	type team struct {
+		Members []string
+		Labels  map[int]string
+	}
+	target := &team{
+		Members: []string{"a", "b", "c", "d", "e"},
+		Labels:  map[int]string{0: "x", 2: "y"},
+	}
+
+	type test struct {
+		name      string
+		path      string
+		want      []any
+		wantPaths []string
+	}
+	tests := []test{
+		{name: "negative index", path: "Members[-1]", want: []any{"e"}, wantPaths: []string{"Members[4]"}},
+		{name: "negative index out of range", path: "Members[-6]", want: nil},
+		{name: "range", path: "Members[1:3]", want: []any{"b", "c"}, wantPaths: []string{"Members[1]", "Members[2]"}},
+		{name: "open range", path: "Members[-2:]", want: []any{"d", "e"}, wantPaths: []string{"Members[3]", "Members[4]"}},
+		{name: "step range", path: "Members[::2]", want: []any{"a", "c", "e"}, wantPaths: []string{"Members[0]", "Members[2]", "Members[4]"}},
+		{name: "reverse range", path: "Members[::-2]", want: []any{"e", "c", "a"}, wantPaths: []string{"Members[4]", "Members[2]", "Members[0]"}},
+		{name: "clamped range", path: "Members[3:10]", want: []any{"d", "e"}, wantPaths: []string{"Members[3]", "Members[4]"}},
+		{name: "union", path: "Members[0,2,-1,9]", want: []any{"a", "c", "e"}, wantPaths: []string{"Members[0]", "Members[2]", "Members[4]"}},
+		{name: "union of map keys", path: "Labels[2,1,0]", want: []any{"y", "x"}, wantPaths: []string{`Labels["2"]`, `Labels["0"]`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []any
+			var gotPaths []string
+			goval.Each(target, path, func(v any, pathInfo goval.PathInfo) {
+				got = append(got, v)
+				gotPaths = append(gotPaths, pathInfo.ResolvedPath.String())
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Each(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("Each(%v) ResolvedPath = %v, want %v", tt.path, gotPaths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestEachPathInfo(t *testing.T) {
+	type member struct {
+		Name string `json:"name"`
+	}
+	type team struct {
+		Members []*member
+		Leaders map[string]member
+	}
+	target := &team{
+		Members: []*member{{Name: "Alice"}, {Name: "Bob"}},
+		Leaders: map[string]member{"dev": {Name: "Carol"}},
+	}
+
+	type want struct {
+		names []string
+		keys  []any
+		depth int
+		tag   string
+	}
+	type test struct {
+		name  string
+		path  string
+		wants []want
+	}
+	tests := []test{
+		{
+			name: "all slice fields",
+			path: "Members[*].Name",
+			wants: []want{
+				{names: []string{"Members", "Name"}, keys: []any{0}, depth: 2, tag: `json:"name"`},
+				{names: []string{"Members", "Name"}, keys: []any{1}, depth: 2, tag: `json:"name"`},
+			},
+		},
+		{
+			name: "all map fields",
+			path: "Leaders[*].Name",
+			wants: []want{
+				{names: []string{"Leaders", "Name"}, keys: []any{"dev"}, depth: 2, tag: `json:"name"`},
+			},
+		},
+		{
+			name: "recursive fields",
+			path: "..Name",
+			wants: []want{
+				{names: []string{"Members", "Name"}, keys: []any{0}, depth: 2, tag: `json:"name"`},
+				{names: []string{"Members", "Name"}, keys: []any{1}, depth: 2, tag: `json:"name"`},
+				{names: []string{"Leaders", "Name"}, keys: []any{"dev"}, depth: 2, tag: `json:"name"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gots []want
+			goval.Each(target, path, func(v any, pathInfo goval.PathInfo) {
+				var names []string
+				for _, p := range pathInfo.ResolvedPath.Split() {
+					names = append(names, p.Name())
+				}
+				gots = append(gots, want{
+					names: names,
+					keys:  pathInfo.Keys,
+					depth: pathInfo.Depth,
+					tag:   string(pathInfo.StructField.Tag),
+				})
+			})
+			if !reflect.DeepEqual(gots, tt.wants) {
+				t.Errorf("Each(%v) pathInfo = %v, want %v", tt.path, gots, tt.wants)
+			}
+		})
+	}
+}
+
+func TestEachTagNames(t *testing.T) {
+	type audit struct {
+		CreatedAt string `json:"created_at" yaml:"createdAt"`
+	}
+	type member struct {
+		audit
+		Name  string `json:"name,omitempty" goval:"display_name"`
+		Email string `json:"-"`
+		Age   int
+	}
+	type team struct {
+		Members []member `json:"members"`
+	}
+	target := &team{
+		Members: []member{
+			{audit: audit{CreatedAt: "2024"}, Name: "Alice", Email: "a@example.com", Age: 25},
+			{Name: "Bob", Age: 40},
+		},
+	}
+
+	type test struct {
+		name      string
+		path      string
+		opts      []goval.Option
+		want      []any
+		wantPaths []string
+	}
+	tests := []test{
+		{
+			name:      "json names",
+			path:      "members[*].name",
+			opts:      []goval.Option{goval.WithTagNames("json")},
+			want:      []any{"Alice", "Bob"},
+			wantPaths: []string{"members[0].name", "members[1].name"},
+		},
+		{
+			name:      "fall back to go names",
+			path:      "members[0].Age",
+			opts:      []goval.Option{goval.WithTagNames("json")},
+			want:      []any{25},
+			wantPaths: []string{"members[0].Age"},
+		},
+		{
+			name:      "ignored tag",
+			path:      "members[0].Email",
+			opts:      []goval.Option{goval.WithTagNames("json")},
+			want:      []any{"a@example.com"},
+			wantPaths: []string{"members[0].Email"},
+		},
+		{
+			name:      "promoted field",
+			path:      "Members[0].createdAt",
+			opts:      []goval.Option{goval.WithTagNames("yaml")},
+			want:      []any{"2024"},
+			wantPaths: []string{"Members[0].createdAt"},
+		},
+		{
+			name:      "goval tag",
+			path:      "Members[1].display_name",
+			opts:      []goval.Option{goval.WithTagNames()},
+			want:      []any{"Bob"},
+			wantPaths: []string{"Members[1].display_name"},
+		},
+		{
+			name:      "tag keys in order",
+			path:      "Members[1].display_name",
+			opts:      []goval.Option{goval.WithTagNames("json", "goval")},
+			want:      []any{"Bob"},
+			wantPaths: []string{"Members[1].display_name"},
+		},
+		{
+			name:      "field wildcard",
+			path:      "members[1].*",
+			opts:      []goval.Option{goval.WithTagNames("json")},
+			want:      []any{"Bob", "", 40},
+			wantPaths: []string{"members[1].name", "members[1].Email", "members[1].Age"},
+		},
+		{
+			name: "without option",
+			path: "members[0].name",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []any
+			var gotPaths []string
+			goval.Each(target, path, func(v any, pathInfo goval.PathInfo) {
+				got = append(got, v)
+				gotPaths = append(gotPaths, pathInfo.ResolvedPath.String())
+			}, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Each(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("Each(%v) ResolvedPath = %v, want %v", tt.path, gotPaths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+type EmbeddedNamed interface {
+	Greeting() string
+}
+
+type embeddedPerson struct {
+	Name string
+}
+
+func (p embeddedPerson) Greeting() string {
+	return "hello " + p.Name
+}
+
+type EmbeddedAudit struct {
+	CreatedBy string
+}
+
+func TestEachEmbedded(t *testing.T) {
+	type member struct {
+		*EmbeddedAudit
+		EmbeddedNamed
+		Age int
+	}
+	type team struct {
+		Leader  any
+		Members []any
+		Owner   member
+		Extra   any
+	}
+	newTeam := func() *team {
+		return &team{
+			Leader: embeddedPerson{Name: "Alice"},
+			Members: []any{
+				&embeddedPerson{Name: "Bob"},
+				embeddedPerson{Name: "Carol"},
+			},
+			Owner: member{EmbeddedNamed: embeddedPerson{Name: "Dave"}},
+			Extra: "x",
+		}
+	}
+
+	type test struct {
+		name string
+		path string
+		want []any
+	}
+	tests := []test{
+		{name: "interface field holding struct", path: "Leader.Name", want: []any{"Alice"}},
+		{name: "interface elements", path: "Members[*].Name", want: []any{"Bob", "Carol"}},
+		{name: "embedded interface", path: "Owner.Name", want: []any{"Dave"}},
+		{name: "nil embedded pointer", path: "Owner.CreatedBy", want: nil},
+		{name: "interface leaf", path: "Extra", want: []any{"x"}},
+		{name: "recursive descent through interfaces", path: "..Name", want: []any{"Alice", "Bob", "Carol", "Dave"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := goval.GetAll[any](newTeam(), path)
+			if len(got) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil embedded pointer in strict mode", func(t *testing.T) {
+		path, _ := goval.Parse("Owner.CreatedBy")
+		_, err := goval.TryGetAll[string](newTeam(), path, goval.Strict())
+		if !errors.As(err, new(*goval.NilPointerError)) {
+			t.Errorf("error = %v, want *goval.NilPointerError", err)
+		}
+	})
+	t.Run("set through interfaces", func(t *testing.T) {
+		target := newTeam()
+		for _, s := range []string{"Leader.Name", "Members[*].Name", "Owner.Name", "Owner.CreatedBy"} {
+			path, _ := goval.Parse(s)
+			goval.SetFunc(target, path, func(v string, _ goval.PathInfo) string {
+				return strings.ToUpper(v) + "!"
+			}, goval.WithCreate())
+		}
+		want := &team{
+			Leader: embeddedPerson{Name: "ALICE!"},
+			Members: []any{
+				&embeddedPerson{Name: "BOB!"},
+				embeddedPerson{Name: "CAROL!"},
+			},
+			Owner: member{EmbeddedAudit: &EmbeddedAudit{CreatedBy: "!"}, EmbeddedNamed: embeddedPerson{Name: "DAVE!"}},
+			Extra: "x",
+		}
+		if !reflect.DeepEqual(target, want) {
+			t.Errorf("SetFunc() = %+v, want %+v", target, want)
+		}
+	})
+	t.Run("replace interface value", func(t *testing.T) {
+		target := newTeam()
+		path, _ := goval.Parse("Members[1]")
+		goval.Set[any](target, path, embeddedPerson{Name: "Eve"})
+		if got := target.Members[1]; got != (embeddedPerson{Name: "Eve"}) {
+			t.Errorf("Members[1] = %v, want Eve", got)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package goval
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envSeparator separates the path segments in the environment variable names.
+const envSeparator = "__"
+
+// BindEnv updates the fields of the target by the environment variables with the prefix, using SetString.
+// The variable names after the prefix are the path segments separated by "__", where
+// the field names are matched ignoring case and underscores, numbers are slice indexes and others are map keys.
+// e.g. APP_DATABASE__HOST for "Database.Host", APP_MEMBERS__0__NAME for "Members[0].Name", APP_LABELS__env for "Labels[env]"
+//
+// Nil pointers, slices and maps on the paths are created. Variables matching no field, or naming the fields
+// which SetString can not parse such as structs, are ignored, unless Strict is given.
+func BindEnv(target any, prefix string, opts ...Option) error {
+	o := newOptions(opts)
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return &InvalidTargetError{Type: t}
+	}
+	env := os.Environ()
+	sort.Strings(env)
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, prefix) || name == prefix {
+			continue
+		}
+		path, err := envPath(t, strings.Split(name[len(prefix):], envSeparator), o)
+		if err != nil {
+			if o.strict {
+				return err
+			}
+			continue
+		}
+		if err := TrySetString(target, path, value, append(opts[:len(opts):len(opts)], WithCreate())...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportEnv returns the environment variable names of the settable fields of the target with the prefix,
+// which BindEnv binds. Field names are converted to upper snake case. e.g. APP_DATABASE__CREATED_AT
+// Elements of slices and entries of maps are listed as many as the target holds.
+func ExportEnv(target any, prefix string, opts ...Option) []string {
+	e := &envExporter{
+		opts:    newOptions(opts),
+		prefix:  prefix,
+		visited: visitSet{},
+		types:   map[reflect.Type]bool{},
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr {
+		return nil
+	}
+	e.export(v, v.Type(), nil, false)
+	return e.names
+}
+
+// envPath resolves the environment variable segments against the type to the path.
+func envPath(t reflect.Type, segs []string, o options) (Path, error) {
+	var b Builder
+	field := false // true if the last segment is a field, which can be indexed.
+	for _, seg := range segs {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch {
+		case seg == "":
+			return nil, &FieldNotFoundError{Path: &path{parent: b.Path, name: seg, ptype: PathTypeValue}, Type: t}
+		case t.Kind() == reflect.Struct:
+			sf, ok := envField(t, seg, o)
+			if !ok || !regValue.MatchString(o.fieldName(sf)) { // tag names such as "created-at" can not be expressed in paths.
+				return nil, &FieldNotFoundError{Path: &path{parent: b.Path, name: seg, ptype: PathTypeValue}, Type: t}
+			}
+			b = b.Field(o.fieldName(sf))
+			t = sf.Type
+			field = true
+			continue
+		case field && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && regIndex.MatchString(seg):
+			i, _ := strconv.Atoi(seg)
+			b = b.Index(i)
+		case field && t.Kind() == reflect.Map:
+			b = b.Key(seg)
+		default:
+			return nil, &InvalidTargetError{Path: b.Path, Type: t}
+		}
+		t = t.Elem()
+		field = false
+	}
+	if !isEnvLeaf(t) {
+		return nil, &InvalidTargetError{Path: b.Path, Type: t}
+	}
+	return b.Path, nil
+}
+
+// envField returns the exported field matching the segment, ignoring case and underscores.
+func envField(t reflect.Type, seg string, o options) (reflect.StructField, bool) {
+	seg = envFold(seg)
+	var found reflect.StructField
+	var ok bool
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() || envFold(o.fieldName(sf)) != seg && envFold(sf.Name) != seg {
+			continue
+		}
+		if !ok || len(sf.Index) < len(found.Index) {
+			found, ok = sf, true
+		}
+	}
+	return found, ok
+}
+
+func envFold(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, "_", ""))
+}
+
+// envName converts the field name to upper snake case. e.g. CREATED_AT for CreatedAt
+func envName(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// isEnvLeaf reports whether SetString can parse the text to the type.
+func isEnvLeaf(t reflect.Type) bool {
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return isEnvLeaf(t.Elem())
+	case reflect.Slice:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		return elem.Kind() != reflect.Slice && isEnvLeaf(elem)
+	case reflect.Interface, reflect.Struct, reflect.Map, reflect.Array, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128, reflect.Uintptr:
+		return false
+	}
+	return true
+}
+
+// envExporter collects the environment variable names of the settable fields.
+type envExporter struct {
+	opts    options
+	prefix  string
+	names   []string
+	visited visitSet              // pointers on the current path, to stop at the cycles.
+	types   map[reflect.Type]bool // struct types expanded without values, to stop at the recursive types.
+}
+
+// export collects the names of v, which is invalid if the value is not known, such as the elem of nil pointer.
+// field is true if the last segment is a field, which can be indexed.
+func (e *envExporter) export(v reflect.Value, t reflect.Type, segs []string, field bool) {
+	if len(segs) > 0 && isEnvLeaf(t) {
+		e.names = append(e.names, e.prefix+strings.Join(segs, envSeparator))
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if !v.IsValid() || v.IsNil() {
+			e.export(reflect.Value{}, t.Elem(), segs, field)
+			return
+		}
+		if e.visited.add(v) {
+			defer delete(e.visited, visit{ptr: v.Pointer(), typ: v.Type()})
+			e.export(v.Elem(), t.Elem(), segs, field)
+		}
+	case reflect.Struct:
+		if !v.IsValid() {
+			if e.types[t] {
+				return
+			}
+			e.types[t] = true
+			defer delete(e.types, t)
+		}
+		for _, sf := range reflect.VisibleFields(t) {
+			if !sf.IsExported() || sf.Anonymous && !isEnvLeaf(sf.Type) {
+				continue // the fields of the embedded struct are listed as promoted fields.
+			}
+			var fv reflect.Value
+			if v.IsValid() {
+				fv, _ = v.FieldByIndexErr(sf.Index)
+			}
+			e.export(fv, sf.Type, append(segs[:len(segs):len(segs)], envName(e.opts.fieldName(sf))), true)
+		}
+	case reflect.Slice, reflect.Array:
+		if !field || !v.IsValid() {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			e.export(v.Index(i), t.Elem(), append(segs[:len(segs):len(segs)], strconv.Itoa(i)), false)
+		}
+	case reflect.Map:
+		if !field || !v.IsValid() {
+			return
+		}
+		for _, key := range sortedMapKeys(v) {
+			e.export(v.MapIndex(key), t.Elem(), append(segs[:len(segs):len(segs)], keyString(key)), false)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package goval_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tadjp/goval"
+)
+
+type envMember struct {
+	Name string
+	Age  int
+}
+
+type envDatabase struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+}
+
+type envNode struct {
+	Name   string
+	Parent *envNode
+}
+
+type envConfig struct {
+	Database  *envDatabase
+	Members   []envMember
+	Labels    map[string]string
+	Tags      []string
+	CreatedAt time.Time `json:"created_at"`
+	Root      *envNode
+	Extra     any
+	secret    string
+}
+
+func TestBindEnv(t *testing.T) {
+	t.Setenv("APP_DATABASE__HOST", "db.local")
+	t.Setenv("APP_DATABASE__PORT", "5432")
+	t.Setenv("APP_DATABASE__TIMEOUT", "5s")
+	t.Setenv("APP_MEMBERS__1__NAME", "Bob")
+	t.Setenv("APP_MEMBERS__0__NAME", "Alice")
+	t.Setenv("APP_LABELS__env", "prod")
+	t.Setenv("APP_TAGS", "a,b")
+	t.Setenv("APP_CREATED_AT", "2024-01-02T03:04:05Z")
+	t.Setenv("APP_UNKNOWN", "x")
+	t.Setenv("OTHER_DATABASE__HOST", "x")
+
+	cfg := &envConfig{}
+	if err := goval.BindEnv(cfg, "APP_"); err != nil {
+		t.Fatal(err)
+	}
+	want := &envConfig{
+		Database:  &envDatabase{Host: "db.local", Port: 5432, Timeout: 5 * time.Second},
+		Members:   []envMember{{Name: "Alice"}, {Name: "Bob"}},
+		Labels:    map[string]string{"env": "prod"},
+		Tags:      []string{"a", "b"},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("BindEnv() = %+v, want %+v", cfg, want)
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		err := goval.BindEnv(&envConfig{}, "APP_", goval.Strict())
+		if !errors.As(err, new(*goval.FieldNotFoundError)) {
+			t.Errorf("BindEnv() error = %v, want *goval.FieldNotFoundError", err)
+		}
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("APP_DATABASE__PORT", "http")
+		err := goval.BindEnv(&envConfig{}, "APP_")
+		if !errors.As(err, new(*goval.ConversionError)) {
+			t.Errorf("BindEnv() error = %v, want *goval.ConversionError", err)
+		}
+	})
+	t.Run("non-leaf field", func(t *testing.T) {
+		t.Setenv("NL_DATABASE", "foo")
+		t.Setenv("NL_DATABASE__HOST", "db.local")
+		cfg := &envConfig{}
+		if err := goval.BindEnv(cfg, "NL_"); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Database == nil || cfg.Database.Host != "db.local" {
+			t.Errorf("BindEnv() = %+v, want Database.Host db.local", cfg.Database)
+		}
+		err := goval.BindEnv(&envConfig{}, "NL_", goval.Strict())
+		if !errors.As(err, new(*goval.InvalidTargetError)) {
+			t.Errorf("BindEnv() error = %v, want *goval.InvalidTargetError", err)
+		}
+	})
+	t.Run("tag names", func(t *testing.T) {
+		t.Setenv("TAG_CREATED_AT", "2024-01-02T03:04:05Z")
+		cfg := &envConfig{}
+		if err := goval.BindEnv(cfg, "TAG_", goval.WithTagNames("json")); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.CreatedAt.IsZero() {
+			t.Error("BindEnv() did not bind CreatedAt")
+		}
+	})
+}
+
+func TestExportEnv(t *testing.T) {
+	root := &envNode{Name: "root"}
+	root.Parent = root
+	cfg := &envConfig{
+		Members: []envMember{{Name: "Alice"}},
+		Labels:  map[string]string{"env": "prod"},
+		Root:    root,
+	}
+	got := goval.ExportEnv(cfg, "APP_")
+	want := []string{
+		"APP_DATABASE__HOST",
+		"APP_DATABASE__PORT",
+		"APP_DATABASE__TIMEOUT",
+		"APP_MEMBERS__0__NAME",
+		"APP_MEMBERS__0__AGE",
+		"APP_LABELS__env",
+		"APP_TAGS",
+		"APP_CREATED_AT",
+		"APP_ROOT__NAME",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportEnv() = %v, want %v", got, want)
+	}
+
+	t.Run("shared pointers", func(t *testing.T) {
+		type server struct{ Host string }
+		shared := &server{}
+		got := goval.ExportEnv(&struct{ A, B *server }{A: shared, B: shared}, "APP_")
+		if want := []string{"APP_A__HOST", "APP_B__HOST"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ExportEnv() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("recursive types", func(t *testing.T) {
+		got := goval.ExportEnv(&envNode{}, "APP_")
+		if want := []string{"APP_NAME", "APP_PARENT__NAME"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ExportEnv() = %v, want %v", got, want)
+		}
+	})
+}
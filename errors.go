@@ -0,0 +1,185 @@
+package goval
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InvalidTargetError is returned when the target is not a pointer to a struct.
+type InvalidTargetError struct {
+	Path Path         // path segment applied to the target.
+	Type reflect.Type // type of the target.
+}
+
+func (e *InvalidTargetError) Error() string {
+	return fmt.Sprintf("invalid target at %s: %v, must be pointer to struct", pathString(e.Path), e.Type)
+}
+
+// TypeMismatchError is returned when the field value does not match the requested type.
+type TypeMismatchError struct {
+	Path Path         // path segment of the field.
+	Want reflect.Type // requested type.
+	Got  reflect.Type // actual type.
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("type mismatch at %s: %v, want %v", pathString(e.Path), e.Got, e.Want)
+}
+
+// ConversionError is returned by SetAny when the value can not be converted to the field type.
+// e.g. overflow, invalid text for encoding.TextUnmarshaler
+type ConversionError struct {
+	Path  Path         // path segment of the field.
+	Value any          // value to convert.
+	Type  reflect.Type // type of the field.
+	Err   error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("cannot convert %v to %v at %s: %v", e.Value, e.Type, pathString(e.Path), e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// UnsettableFieldError is returned when the field can not be updated. e.g. unexported field.
+type UnsettableFieldError struct {
+	Path Path         // path segment of the field.
+	Type reflect.Type // type of the field.
+}
+
+func (e *UnsettableFieldError) Error() string {
+	return fmt.Sprintf("unsettable field at %s: %v", pathString(e.Path), e.Type)
+}
+
+// UnexportedFieldError is returned when the access policy forbids the access to the unexported field.
+// It wraps UnsettableFieldError when the field is written.
+type UnexportedFieldError struct {
+	Path  Path         // path segment of the field.
+	Type  reflect.Type // type of the field.
+	Write bool         // true if the field is written.
+}
+
+func (e *UnexportedFieldError) Error() string {
+	op := "read"
+	if e.Write {
+		op = "write"
+	}
+	return fmt.Sprintf("cannot %s unexported field at %s: %v", op, pathString(e.Path), e.Type)
+}
+
+func (e *UnexportedFieldError) Unwrap() error {
+	if !e.Write {
+		return nil
+	}
+	return &UnsettableFieldError{Path: e.Path, Type: e.Type}
+}
+
+// ParseError is returned when the path string can not be parsed.
+type ParseError struct {
+	Input  string // whole path string.
+	Offset int    // byte offset of the invalid token in Input.
+	Token  string // invalid token.
+	Err    error  // underlying error, if any.
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("invalid defined path %q: token %q at offset %d", e.Input, e.Token, e.Offset)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FieldNotFoundError is returned in strict mode when the struct has no field of the path name.
+type FieldNotFoundError struct {
+	Path Path         // path segment of the field.
+	Type reflect.Type // struct type searched for the field.
+}
+
+func (e *FieldNotFoundError) Error() string {
+	return fmt.Sprintf("field not found at %s: %v has no field %s", pathString(e.Path), e.Type, e.Path.Name())
+}
+
+// IndexOutOfRangeError is returned in strict mode when the index exceeds the length of the collection.
+type IndexOutOfRangeError struct {
+	Path  Path // path segment of the collection.
+	Index int  // requested index.
+	Len   int  // length of the collection.
+}
+
+func (e *IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("index out of range at %s: index %d with length %d", pathString(e.Path), e.Index, e.Len)
+}
+
+// KeyNotFoundError is returned in strict mode when the map has no entry of the key.
+type KeyNotFoundError struct {
+	Path Path   // path segment of the map.
+	Key  string // requested key.
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return fmt.Sprintf("key not found at %s: %q", pathString(e.Path), e.Key)
+}
+
+// NilPointerError is returned in strict mode when the path goes through a nil pointer.
+type NilPointerError struct {
+	Path Path         // path segment of the nil value.
+	Type reflect.Type // type of the nil value.
+}
+
+func (e *NilPointerError) Error() string {
+	return fmt.Sprintf("nil pointer at %s: %v", pathString(e.Path), e.Type)
+}
+
+// InvalidOperationError is returned when the collection operation is not supported by the field or the path.
+// e.g. append to an array, insert to "Members[*]"
+type InvalidOperationError struct {
+	Op   string       // operation name.
+	Path Path         // path segment of the collection.
+	Type reflect.Type // type of the collection, nil if the path does not support the operation.
+}
+
+func (e *InvalidOperationError) Error() string {
+	if e.Type == nil {
+		return fmt.Sprintf("invalid operation at %s: cannot %s", pathString(e.Path), e.Op)
+	}
+	return fmt.Sprintf("invalid operation at %s: cannot %s %v", pathString(e.Path), e.Op, e.Type)
+}
+
+// MatchCountError is returned by MustGet when the path does not match exactly one field.
+type MatchCountError struct {
+	Path  Path // required path.
+	Count int  // number of the matched fields.
+}
+
+func (e *MatchCountError) Error() string {
+	return fmt.Sprintf("%d fields match %s, want exactly one", e.Count, pathString(e.Path))
+}
+
+// MaxDepthError is returned when the traversal goes deeper than the MaxDepth option.
+type MaxDepthError struct {
+	Path     Path // resolved path reaching the limit.
+	MaxDepth int
+}
+
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf("max depth %d exceeded at %s", e.MaxDepth, pathString(e.Path))
+}
+
+// pathString returns the path string, or "<root>" for nil path.
+func pathString(p Path) string {
+	if p == nil {
+		return "<root>"
+	}
+	return p.String()
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,85 @@
+package goval
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldByName returns the struct field named by the path segment.
+// The names given by the tag keys of the options are matched first, then the Go field names.
+func (o options) fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for _, key := range o.tags {
+		var found reflect.StructField
+		var ok bool
+		for _, sf := range reflect.VisibleFields(t) {
+			if !sf.IsExported() || tagName(sf, key) != name {
+				continue
+			}
+			if !ok || len(sf.Index) < len(found.Index) { // the shallowest field wins, like the promoted fields.
+				found, ok = sf, true
+			}
+		}
+		if ok {
+			return found, true
+		}
+	}
+	return t.FieldByName(name)
+}
+
+// fieldName returns the name of the struct field in the resolved paths.
+func (o options) fieldName(sf reflect.StructField) string {
+	for _, key := range o.tags {
+		if name := tagName(sf, key); name != "" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// tagName returns the name in the struct tag. e.g. "created_at" for `json:"created_at,omitempty"`
+func tagName(sf reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(sf.Tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// fieldByIndex returns the nested field by the index, allocating the nil embedded pointers with create.
+// the nil embedded pointer is returned with false, if it can not be allocated.
+func fieldByIndex(v reflect.Value, index []int, create bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !create || !v.CanSet() {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// embeddedInterface returns the embedded interface field holding the struct with the named field.
+func (o options) embeddedInterface(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.Anonymous || sf.Type.Kind() != reflect.Interface || v.Field(i).IsNil() {
+			continue
+		}
+		t := v.Field(i).Elem().Type()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := o.fieldByName(t, name); ok {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,423 @@
+package goval
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// filterExpr is a predicate of the filter path segment. e.g. Members[?(@.Age > 30)]
+//
+// grammar:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) operand ]
+//	operand    = "@" [ "." path ] | number | string | "true" | "false" | "nil" | "null"
+type filterExpr interface {
+	eval(w *walker, v reflect.Value) (bool, error)
+}
+
+type filterOr struct {
+	left, right filterExpr
+}
+
+func (f *filterOr) eval(w *walker, v reflect.Value) (bool, error) {
+	ok, err := f.left.eval(w, v)
+	if err != nil || ok {
+		return ok, err
+	}
+	return f.right.eval(w, v)
+}
+
+type filterAnd struct {
+	left, right filterExpr
+}
+
+func (f *filterAnd) eval(w *walker, v reflect.Value) (bool, error) {
+	ok, err := f.left.eval(w, v)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return f.right.eval(w, v)
+}
+
+type filterNot struct {
+	expr filterExpr
+}
+
+func (f *filterNot) eval(w *walker, v reflect.Value) (bool, error) {
+	ok, err := f.expr.eval(w, v)
+	return !ok, err
+}
+
+// filterCompare compares the operands. the comparison is true if any value of the current element operand matches.
+type filterCompare struct {
+	left, right filterOperand
+	op          string
+}
+
+func (f *filterCompare) eval(w *walker, v reflect.Value) (bool, error) {
+	lefts, err := f.left.values(w, v)
+	if err != nil {
+		return false, err
+	}
+	rights, err := f.right.values(w, v)
+	if err != nil {
+		return false, err
+	}
+	for _, l := range lefts {
+		for _, r := range rights {
+			if compare(l, r, f.op) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// filterExists is true if any value of the operand exists, and it is neither nil nor false.
+type filterExists struct {
+	operand filterOperand
+}
+
+func (f *filterExists) eval(w *walker, v reflect.Value) (bool, error) {
+	values, err := f.operand.values(w, v)
+	if err != nil {
+		return false, err
+	}
+	for _, value := range values {
+		if b, ok := value.(bool); value != nil && (!ok || b) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// filterOperand is a literal or a path relative to the current element.
+type filterOperand struct {
+	current bool // true if the operand refers to the current element, "@".
+	path    Path // path relative to the current element, nil for the element itself.
+	literal any
+}
+
+func (o filterOperand) values(w *walker, v reflect.Value) ([]any, error) {
+	if !o.current {
+		return []any{o.literal}, nil
+	}
+	if o.path == nil {
+		return []any{fieldValueAny(v)}, nil
+	}
+
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Ptr:
+	case v.CanAddr():
+		v = v.Addr()
+	case v.IsValid():
+		cp := reflect.New(v.Type())
+		cp.Elem().Set(v)
+		v = cp
+	default:
+		return nil, nil
+	}
+	if v.IsNil() || elem(v).Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var values []any
+	sub := &walker{
+		opts: w.opts,
+		fn: func(v any, _ PathInfo) error {
+			values = append(values, v)
+			return nil
+		},
+		ctx: w.ctx,
+	}
+	sub.opts.strict = false
+	sub.opts.mode = ByValue // operands are compared as values.
+	if err := sub.each(v, o.path.Split(), PathInfo{RequirePath: o.path}); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// compare compares the values by the operator. numbers are compared as float64.
+func compare(l, r any, op string) bool {
+	if lf, ok := toFloat(l); ok {
+		rf, ok := toFloat(r)
+		if !ok {
+			return op == "!="
+		}
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+		return false
+	}
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		if !ok {
+			return op == "!="
+		}
+		switch op {
+		case "==":
+			return ls == rs
+		case "!=":
+			return ls != rs
+		case "<":
+			return ls < rs
+		case "<=":
+			return ls <= rs
+		case ">":
+			return ls > rs
+		case ">=":
+			return ls >= rs
+		}
+		return false
+	}
+	if (l != nil && !reflect.TypeOf(l).Comparable()) || (r != nil && !reflect.TypeOf(r).Comparable()) {
+		return op == "!="
+	}
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// parseFilter parses the filter expression. e.g. @.Age > 30 && @.Role == "admin"
+func parseFilter(src string) (filterExpr, error) {
+	p := &filterParser{src: src}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, errors.New("unexpected " + strconv.Quote(p.src[p.pos:]) + " in filter")
+	}
+	return expr, nil
+}
+
+type filterParser struct {
+	src string
+	pos int
+}
+
+func (p *filterParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips the token if the rest of source starts with it.
+func (p *filterParser) consume(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	switch {
+	case p.consume("!"):
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNot{expr: expr}, nil
+	case p.consume("("):
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, errors.New("missing ) in filter")
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range filterOps {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &filterCompare{left: left, right: right, op: op}, nil
+		}
+	}
+	if !left.current {
+		return nil, errors.New("filter must refer to the current element @")
+	}
+	return &filterExists{operand: left}, nil
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	p.skipSpace()
+	rest := p.src[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, "@"):
+		p.pos++
+		if !strings.HasPrefix(rest, "@.") {
+			return filterOperand{current: true}, nil
+		}
+		p.pos++
+		start := p.pos
+		p.scanPath()
+		relPath, err := Parse(p.src[start:p.pos])
+		if err != nil {
+			return filterOperand{}, err
+		}
+		return filterOperand{current: true, path: relPath}, nil
+	case strings.HasPrefix(rest, `"`):
+		start := p.pos
+		for p.pos++; p.pos < len(p.src) && p.src[p.pos] != '"'; p.pos++ {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+		p.pos++
+		if p.pos > len(p.src) {
+			return filterOperand{}, errors.New("unterminated string in filter")
+		}
+		s, err := strconv.Unquote(p.src[start:p.pos])
+		if err != nil {
+			return filterOperand{}, err
+		}
+		return filterOperand{literal: s}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && (isWordChar(p.src[p.pos]) || strings.IndexByte("+-.", p.src[p.pos]) >= 0) {
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	switch word {
+	case "true":
+		return filterOperand{literal: true}, nil
+	case "false":
+		return filterOperand{literal: false}, nil
+	case "nil", "null":
+		return filterOperand{literal: nil}, nil
+	}
+	f, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return filterOperand{}, errors.New("invalid operand " + strconv.Quote(word) + " in filter")
+	}
+	return filterOperand{literal: f}, nil
+}
+
+// scanPath moves the position to the end of the relative path.
+func (p *filterParser) scanPath() {
+	var depth int
+	for ; p.pos < len(p.src); p.pos++ {
+		c := p.src[p.pos]
+		switch {
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '"' && depth > 0:
+			for p.pos++; p.pos < len(p.src) && p.src[p.pos] != '"'; p.pos++ {
+				if p.src[p.pos] == '\\' {
+					p.pos++
+				}
+			}
+		case depth == 0 && !isWordChar(c) && c != '.' && c != '*':
+			return
+		}
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,138 @@
+package goval_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+func TestFilter(t *testing.T) {
+	type address struct {
+		Country string
+	}
+	type member struct {
+		Name    string
+		Age     int
+		Role    string
+		Active  bool
+		Address *address
+		Tags    []string
+	}
+	type team struct {
+		Members []member
+		ByName  map[string]*member
+		Tags    []string
+	}
+
+	type test struct {
+		name    string
+		path    string
+		want    []any
+		wantErr bool
+	}
+
+	alice := member{Name: "Alice", Age: 25, Role: "admin", Active: true, Address: &address{Country: "JP"}, Tags: []string{"a"}}
+	bob := member{Name: "Bob", Age: 40, Role: "member", Tags: []string{"a", "b"}}
+	carol := member{Name: "Carol", Age: 31, Role: "admin", Address: &address{Country: "US"}}
+	target := &team{
+		Members: []member{alice, bob, carol},
+		ByName: map[string]*member{
+			"alice": &alice,
+			"bob":   &bob,
+			"carol": &carol,
+		},
+		Tags: []string{"x", "y", "z"},
+	}
+
+	tests := []test{
+		{name: "number comparison", path: "Members[?(@.Age > 30)].Name", want: []any{"Bob", "Carol"}},
+		{name: "string comparison", path: `Members[?(@.Role == "admin")].Name`, want: []any{"Alice", "Carol"}},
+		{name: "not equal", path: `Members[?(@.Role != "admin")].Name`, want: []any{"Bob"}},
+		{name: "and", path: `Members[?(@.Role == "admin" && @.Age >= 30)].Name`, want: []any{"Carol"}},
+		{name: "or", path: `Members[?(@.Age < 30 || @.Age > 35)].Name`, want: []any{"Alice", "Bob"}},
+		{name: "not", path: `Members[?(!(@.Age < 30))].Name`, want: []any{"Bob", "Carol"}},
+		{name: "bool field", path: `Members[?(@.Active)].Name`, want: []any{"Alice"}},
+		{name: "bool literal", path: `Members[?(@.Active == false)].Name`, want: []any{"Bob", "Carol"}},
+		{name: "nested field", path: `Members[?(@.Address.Country == "US")].Name`, want: []any{"Carol"}},
+		{name: "existing field", path: `Members[?(@.Address)].Name`, want: []any{"Alice", "Carol"}},
+		{name: "any of collection", path: `Members[?(@.Tags[*] == "b")].Name`, want: []any{"Bob"}},
+		{name: "map values", path: `ByName[?(@.Age > 30)].Name`, want: []any{"Bob", "Carol"}},
+		{name: "current element", path: `Tags[?(@ != "y")]`, want: []any{"x", "z"}},
+		{name: "no match", path: `Members[?(@.Age > 100)].Name`, want: []any{}},
+		{name: "invalid operand", path: `Members[?(@.Age > thirty)].Name`, wantErr: true},
+		{name: "missing current element", path: `Members[?(true)].Name`, wantErr: true},
+		{name: "unclosed group", path: `Members[?((@.Age > 3)].Name`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := goval.GetAll[any](target, path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterSetFunc(t *testing.T) {
+	type member struct {
+		Name string
+		Role string
+	}
+	type team struct {
+		Members []*member
+	}
+	target := &team{
+		Members: []*member{
+			{Name: "Alice", Role: "admin"},
+			{Name: "Bob", Role: "member"},
+		},
+	}
+	path, _ := goval.Parse(`Members[?(@.Role == "admin")].Name`)
+	goval.SetFunc[string](target, path, func(v string, pathInfo goval.PathInfo) string {
+		return strings.ToUpper(v)
+	})
+	if target.Members[0].Name != "ALICE" || target.Members[1].Name != "Bob" {
+		t.Errorf("SetFunc(%v) = %v, %v", path, target.Members[0].Name, target.Members[1].Name)
+	}
+}
+
+func TestFilterValueMode(t *testing.T) {
+	type member struct {
+		Name string
+		Age  int
+	}
+	type team struct {
+		Members []member
+	}
+	newTeam := func() *team {
+		return &team{Members: []member{{Name: "Alice", Age: 25}, {Name: "Bob", Age: 40}, {Name: "Carol", Age: 31}}}
+	}
+	path, _ := goval.Parse("Members[?(@.Age > 30)].Name")
+	deletePath, _ := goval.Parse("Members[?(@.Age > 30)]")
+
+	for _, mode := range []goval.ValueMode{goval.ByValue, goval.ByPointer, goval.RawValue} {
+		opt := goval.WithValueMode(mode)
+		if got := goval.Count(newTeam(), path, opt); got != 2 {
+			t.Errorf("Count() with mode %v = %d, want 2", mode, got)
+		}
+		target := newTeam()
+		err := goval.DeleteWhere(target, deletePath, func(_ any, _ goval.PathInfo) bool {
+			return true
+		}, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []member{{Name: "Alice", Age: 25}}; !reflect.DeepEqual(target.Members, want) {
+			t.Errorf("DeleteWhere() with mode %v = %v, want %v", mode, target.Members, want)
+		}
+	}
+}
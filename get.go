@@ -1,14 +1,111 @@
 package goval
 
-// GetAll get field values
-func GetAll[T any](target any, path Path) []T {
+import "reflect"
+
+// GetAll get field values. nil pointer leaves are returned as the zero value of T.
+func GetAll[T any](target any, path Path, opts ...Option) []T {
+	s, err := TryGetAll[T](target, path, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// TryGetAll is like GetAll but returns an error instead of panicking.
+func TryGetAll[T any](target any, path Path, opts ...Option) ([]T, error) {
 	s := make([]T, 0)
-	Each(target, path, func(v any, pathInfo PathInfo) {
-		r, ok := v.(T)
-		if !ok {
-			panic("invalid type assign")
+	err := tryEach(target, path, func(v any, pathInfo PathInfo) error {
+		r, err := as[T](v, pathInfo)
+		if err != nil {
+			return err
 		}
 		s = append(s, r)
-	})
+		return nil
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the value of the field specified in the path.
+// false is returned if no field or more than one field matches the path. e.g. "Members[*].Name" with two members
+func Get[T any](target any, path Path, opts ...Option) (T, bool) {
+	s := getN[T](target, path, 2, opts)
+	if len(s) != 1 {
+		var zero T
+		return zero, false
+	}
+	return s[0], true
+}
+
+// GetFirst returns the value of the first field specified in the path, false if no field matches.
+func GetFirst[T any](target any, path Path, opts ...Option) (T, bool) {
+	s := getN[T](target, path, 1, opts)
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s[0], true
+}
+
+// MustGet is like Get but panics with MatchCountError if the path does not match exactly one field.
+func MustGet[T any](target any, path Path, opts ...Option) T {
+	s := getN[T](target, path, 2, opts)
+	if len(s) != 1 {
+		panic(&MatchCountError{Path: path, Count: Count(target, path, opts...)})
+	}
+	return s[0]
+}
+
+// GetOr is like Get but returns the default value if the path does not match exactly one field.
+func GetOr[T any](target any, path Path, defaultValue T, opts ...Option) T {
+	if v, ok := Get[T](target, path, opts...); ok {
+		return v
+	}
+	return defaultValue
+}
+
+// Exists reports whether any field matches the path.
+func Exists(target any, path Path, opts ...Option) bool {
+	var found bool
+	if err := EachE(target, path, func(_ any, _ PathInfo) error {
+		found = true
+		return Stop
+	}, opts...); err != nil {
+		panic(err)
+	}
+	return found
+}
+
+// getN returns the values of at most n fields specified in the path. It panics like GetAll.
+func getN[T any](target any, path Path, n int, opts []Option) []T {
+	s := make([]T, 0, n)
+	err := EachE(target, path, func(v any, pathInfo PathInfo) error {
+		r, err := as[T](v, pathInfo)
+		if err != nil {
+			return err
+		}
+		if s = append(s, r); len(s) == n {
+			return Stop
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		panic(err)
+	}
 	return s
 }
+
+// as returns the field value as T. nil pointers and interfaces are returned as the zero value of T.
+func as[T any](v any, pathInfo PathInfo) (T, error) {
+	if v == nil {
+		var zero T
+		return zero, nil
+	}
+	r, ok := v.(T)
+	if !ok {
+		return r, &TypeMismatchError{Path: pathInfo.ResolvedPath, Want: typeOf[T](), Got: reflect.TypeOf(v)}
+	}
+	return r, nil
+}
@@ -1,6 +1,7 @@
 package goval_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -86,6 +87,104 @@ func TestGetAll(t *testing.T) {
 	}
 }
 
+func TestGetAllNilLeaves(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	target := &struct {
+		Members []*member
+	}{
+		Members: []*member{{Name: "Alice"}, nil},
+	}
+	path, _ := goval.Parse("Members[*]")
+	if got, want := goval.GetAll[member](target, path), []member{{Name: "Alice"}, {}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+	if got, want := goval.GetAll[any](target, path), []any{member{Name: "Alice"}, nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+	if got := goval.Count(target, path); got != 2 {
+		t.Errorf("Count() = %v, want 2", got)
+	}
+}
+
+func TestTryGetAll(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+
+	type args struct {
+		src  any
+		path string
+	}
+	type test struct {
+		name    string
+		args    args
+		want    []string
+		wantErr any
+	}
+
+	// create basic test data
+	defaultTest := func(fn func(tt test) test) test {
+		tt := test{
+			args: args{
+				src: &person{
+					Name: "Alice",
+					Age:  25,
+				},
+			},
+		}
+		return fn(tt)
+	}
+
+	tests := []test{
+		defaultTest(func(tt test) test {
+			tt.name = "get a field value"
+			tt.args.path = "Name"
+			tt.want = []string{"Alice"}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "type mismatch"
+			tt.args.path = "Age"
+			tt.wantErr = new(*goval.TypeMismatchError)
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "non-pointer target"
+			tt.args.src = person{}
+			tt.args.path = "Name"
+			tt.wantErr = new(*goval.InvalidTargetError)
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "non-struct field"
+			tt.args.path = "Name.First"
+			tt.wantErr = new(*goval.InvalidTargetError)
+			return tt
+		}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.args.path)
+			got, err := goval.TryGetAll[string](tt.args.src, path)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TryGetAll(%v) error = %v, want %T", tt.args.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("TryGetAll(%v) error = %v", tt.args.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TryGetAll(%v) = %v, want %v", tt.args.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func ExampleGetAll() {
 	type Member struct {
 		Name string
@@ -118,3 +217,81 @@ func ExampleGetAll() {
 	// [Alice]
 	// [Alice Bob]
 }
+
+func TestGet(t *testing.T) {
+	type member struct {
+		Name string
+		Age  int
+	}
+	type team struct {
+		Name    string
+		Members []member
+	}
+	target := &team{
+		Name:    "TEAM-A",
+		Members: []member{{Name: "Alice", Age: 25}, {Name: "Bob", Age: 40}},
+	}
+	parse := func(s string) goval.Path {
+		p, err := goval.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	type test struct {
+		name      string
+		path      string
+		want      string
+		wantOK    bool
+		wantFirst string
+	}
+	tests := []test{
+		{name: "single field", path: "Members[1].Name", want: "Bob", wantOK: true, wantFirst: "Bob"},
+		{name: "multiple fields", path: "Members[*].Name", wantFirst: "Alice"},
+		{name: "no field", path: "Members[5].Name"},
+		{name: "single match of filter", path: "Members[?(@.Age > 30)].Name", want: "Bob", wantOK: true, wantFirst: "Bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := parse(tt.path)
+			got, ok := goval.Get[string](target, path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Get(%v) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+			first, ok := goval.GetFirst[string](target, path)
+			if first != tt.wantFirst || ok != (tt.wantFirst != "") {
+				t.Errorf("GetFirst(%v) = %q, %v, want %q", tt.path, first, ok, tt.wantFirst)
+			}
+			if got := goval.GetOr(target, path, "none"); tt.wantOK && got != tt.want || !tt.wantOK && got != "none" {
+				t.Errorf("GetOr(%v) = %q", tt.path, got)
+			}
+			if got, want := goval.Exists(target, path), tt.wantFirst != ""; got != want {
+				t.Errorf("Exists(%v) = %v, want %v", tt.path, got, want)
+			}
+		})
+	}
+
+	t.Run("must get", func(t *testing.T) {
+		if got := goval.MustGet[string](target, parse("Name")); got != "TEAM-A" {
+			t.Errorf("MustGet() = %q, want TEAM-A", got)
+		}
+		defer func() {
+			err, _ := recover().(error)
+			var countErr *goval.MatchCountError
+			if !errors.As(err, &countErr) || countErr.Count != 2 {
+				t.Errorf("MustGet() panic = %v, want *goval.MatchCountError with 2 fields", err)
+			}
+		}()
+		goval.MustGet[string](target, parse("Members[*].Name"))
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.As(err, new(*goval.TypeMismatchError)) {
+				t.Errorf("Get() panic = %v, want *goval.TypeMismatchError", err)
+			}
+		}()
+		goval.Get[int](target, parse("Name"))
+	})
+}
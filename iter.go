@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package goval
+
+import (
+	"errors"
+	"iter"
+)
+
+// All returns an iterator over the field values specified in the path, with their path info.
+// The fields are walked lazily while iterating, and the walk stops when the loop breaks.
+// It panics on a type mismatch or an invalid target, like GetAll.
+//
+//	for name, info := range goval.All[string](&team, path) {
+//		fmt.Println(info.ResolvedPath, name)
+//	}
+func All[T any](target any, path Path, opts ...Option) iter.Seq2[T, PathInfo] {
+	return func(yield func(T, PathInfo) bool) {
+		err := tryEach(target, path, func(v any, pathInfo PathInfo) error {
+			r, err := as[T](v, pathInfo)
+			if err != nil {
+				return err
+			}
+			if !yield(r, pathInfo) {
+				return Stop
+			}
+			return nil
+		}, opts)
+		if err != nil && !errors.Is(err, Stop) {
+			panic(err)
+		}
+	}
+}
+
+// Values returns an iterator over the field values specified in the path.
+func Values[T any](target any, path Path, opts ...Option) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range All[T](target, path, opts...) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
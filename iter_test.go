@@ -0,0 +1,56 @@
+//go:build go1.23
+
+package goval_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+func TestAll(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	type team struct {
+		Members []member
+	}
+	target := &team{Members: []member{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}}}
+	path, _ := goval.Parse("Members[*].Name")
+
+	t.Run("all", func(t *testing.T) {
+		var got, gotPaths []string
+		for v, info := range goval.All[string](target, path) {
+			got = append(got, v)
+			gotPaths = append(gotPaths, info.ResolvedPath.String())
+		}
+		if want := []string{"Alice", "Bob", "Carol"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("All() = %v, want %v", got, want)
+		}
+		if want := []string{"Members[0].Name", "Members[1].Name", "Members[2].Name"}; !reflect.DeepEqual(gotPaths, want) {
+			t.Errorf("All() ResolvedPath = %v, want %v", gotPaths, want)
+		}
+	})
+	t.Run("break", func(t *testing.T) {
+		var got []string
+		for v := range goval.Values[string](target, path) {
+			got = append(got, v)
+			if v == "Bob" {
+				break
+			}
+		}
+		if want := []string{"Alice", "Bob"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Values() = %v, want %v", got, want)
+		}
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("All() did not panic")
+			}
+		}()
+		for range goval.All[int](target, path) {
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package goval
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Option configures how the path is resolved against the target.
+type Option func(o *options)
+
+type options struct {
+	strict   bool
+	create   bool
+	tags     []string // struct tag keys naming the fields. e.g. "json"
+	maxDepth int      // max number of resolved segments, 0 for no limit.
+	mode     ValueMode
+	access   AccessPolicy
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Strict reports missing fields, out of range indexes and nil pointers as errors, instead of skipping them.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithCreate allocates nil pointers, grows slices to reach the index and creates maps and map entries on the path,
+// so that Set and SetFunc always land the value. The functions only reading the fields, such as GetAll and Exists, ignore it.
+func WithCreate() Option {
+	return func(o *options) {
+		o.create = true
+	}
+}
+
+// WithTagNames resolves the field names of the path by the struct tags of the keys, in order,
+// falling back to the Go field names. e.g. "created_at" for `json:"created_at"` with WithTagNames("json").
+// Without keys, the `goval:"name"` tag is used.
+func WithTagNames(keys ...string) Option {
+	if len(keys) == 0 {
+		keys = []string{"goval"}
+	}
+	return func(o *options) {
+		o.tags = keys
+	}
+}
+
+// MaxDepth limits the number of the struct fields the traversal goes through, including the fields searched by the recursive descent.
+// The traversal stops with MaxDepthError when the limit is exceeded.
+func MaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// ValueMode specifies how the field values are passed to the callbacks and returned by the getters.
+type ValueMode int
+
+const (
+	// ByValue passes the values of the fields in their declared types, dereferencing the pointers.
+	// e.g. time.Duration for a time.Duration field, Member for a *Member field.
+	// Structs, slices, maps and arrays are passed as their values, so a struct is a copy of the field.
+	ByValue ValueMode = iota
+	// ByPointer passes the pointers to the fields. e.g. *Member for both Member and *Member fields.
+	// Pointer fields are passed as is, and map entries are passed as the pointers to their copies.
+	ByPointer
+	// RawValue passes the reflect.Value of the fields.
+	RawValue
+)
+
+// WithValueMode specifies how the field values are passed. The default is ByValue.
+// Set and SetFunc accept the new values in the same form, pointers to the field types for ByPointer
+// and reflect.Value for RawValue. For ByValue, the values of pointer fields are written to the pointees.
+func WithValueMode(mode ValueMode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// value returns the field value in the value mode.
+// read-only values are copied in ByPointer and RawValue modes, so that they can not be written through the results.
+func (o options) value(fv reflect.Value, readOnly bool) any {
+	if readOnly && o.mode != ByValue && fv.IsValid() {
+		fv = fv.Convert(fv.Type()) // the conversion copies the value, which is not addressable.
+	}
+	switch o.mode {
+	case ByPointer:
+		return fieldValuePtr(fv)
+	case RawValue:
+		return fv
+	}
+	return fieldValue(fv)
+}
+
+// AccessPolicy specifies the access to the unexported fields named in the path.
+// Field wildcards and recursive descents always skip the unexported fields.
+type AccessPolicy int
+
+const (
+	// ReadUnexported reads the unexported fields, but fails to write them with UnexportedFieldError. This is the default.
+	// ByPointer and RawValue modes pass the copies of the unexported fields, so that they can not be written through.
+	ReadUnexported AccessPolicy = iota
+	// ExportedOnly fails to read or write the unexported fields with UnexportedFieldError.
+	ExportedOnly
+	// UnsafeWriteUnexported reads and writes the unexported fields through the unsafe pointers.
+	// It bypasses the encapsulation of the packages, so use it only for test fixtures and the like.
+	UnsafeWriteUnexported
+)
+
+// WithAccess specifies the access policy for the unexported fields. The default is ReadUnexported.
+func WithAccess(policy AccessPolicy) Option {
+	return func(o *options) {
+		o.access = policy
+	}
+}
+
+// exposeUnexported returns the accessible value of the unexported field by reflect.NewAt.
+// the field is returned as is if it is not addressable.
+func exposeUnexported(fv reflect.Value) reflect.Value {
+	if !fv.CanAddr() {
+		return fv
+	}
+	return reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
+}
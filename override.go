@@ -0,0 +1,157 @@
+package goval
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// SetString updates the fields with the raw text parsed according to the field types.
+// It parses numbers, bools, time.Duration, the types implementing encoding.TextUnmarshaler such as time.Time,
+// and comma separated lists for slices. e.g. "5432" for int, "1m30s" for time.Duration, "a, b" for []string
+func SetString(target any, path Path, raw string, opts ...Option) {
+	if err := TrySetString(target, path, raw, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// TrySetString is like SetString but returns an error instead of panicking.
+// ConversionError is returned when the text can not be parsed.
+func TrySetString(target any, path Path, raw string, opts ...Option) error {
+	return tryWrite(target, path, func(_ any, pathInfo PathInfo) error {
+		fieldType := pathInfo.fieldValue.Type()
+		if !pathInfo.canSet() {
+			return pathInfo.setError(fieldType)
+		}
+		newVal, err := parseString(raw, fieldType)
+		if err != nil {
+			return &ConversionError{Path: pathInfo.ResolvedPath, Value: raw, Type: fieldType, Err: err}
+		}
+		pathInfo.set(newVal)
+		return nil
+	}, opts)
+}
+
+// ApplyOverrides updates the fields by the overrides of "path=value" form with SetString.
+// e.g. "Database.Port=5432", "Features[2].Enabled=true"
+// The path ends at the first "=" outside of brackets and quoted keys, and spaces around the path and the value are trimmed.
+//
+// It stops at the first invalid override. Overrides of missing fields are ignored, unless Strict is given.
+// Nil pointers, slices and maps on the paths are created as with WithCreate, like BindEnv.
+func ApplyOverrides(target any, overrides []string, opts ...Option) error {
+	for _, override := range overrides {
+		i, err := indexTopLevel(override, 0, '=')
+		if err != nil {
+			return err
+		}
+		if i < 0 {
+			return &ParseError{Input: override, Token: override, Err: errors.New("missing = in override")}
+		}
+		key, raw := strings.TrimSpace(override[:i]), strings.TrimSpace(override[i+1:])
+		path, err := Parse(key)
+		if err != nil {
+			return err
+		}
+		if err := TrySetString(target, path, raw, append(opts[:len(opts):len(opts)], WithCreate())...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseString parses the raw text to the value of the type t.
+func parseString(raw string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+	if t == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, intBase(raw), t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, intBase(raw), t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	case reflect.Ptr:
+		ev, err := parseString(raw, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(ev)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(raw))
+			break
+		}
+		if raw == "" {
+			v.Set(reflect.MakeSlice(t, 0, 0))
+			break
+		}
+		items := strings.Split(raw, ",")
+		v.Set(reflect.MakeSlice(t, len(items), len(items)))
+		for i, item := range items {
+			ev, err := parseString(strings.TrimSpace(item), t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Index(i).Set(ev)
+		}
+	case reflect.Interface:
+		if !reflect.TypeOf(raw).AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("unsupported type %v", t)
+		}
+		v.Set(reflect.ValueOf(raw))
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %v", t)
+	}
+	return v, nil
+}
+
+// intBase returns 0 for the integers with an explicit "0x", "0o" or "0b" prefix, and 10 otherwise,
+// so that the leading zeros are not taken as an octal prefix.
+func intBase(raw string) int {
+	digits := strings.TrimLeft(raw, "+-")
+	if len(digits) > 2 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		return 0
+	}
+	return 10
+}
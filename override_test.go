@@ -0,0 +1,165 @@
+package goval_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tadjp/goval"
+)
+
+type overrideLevel int
+
+type overrideFeature struct {
+	Name    string
+	Enabled bool
+}
+
+type overrideBackup struct {
+	Host string
+}
+
+type overrideConfig struct {
+	Database struct {
+		Host    string
+		Port    uint16
+		Timeout time.Duration
+		Ratio   float32
+	}
+	Features  []overrideFeature
+	Tags      []string
+	Ports     []int
+	Level     overrideLevel
+	Deadline  time.Time
+	Replicas  *int
+	Backup    *overrideBackup
+	Labels    map[string]string
+	Untouched string
+}
+
+func newOverrideConfig() *overrideConfig {
+	return &overrideConfig{
+		Features:  []overrideFeature{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		Labels:    map[string]string{},
+		Untouched: "x",
+	}
+}
+
+func TestSetString(t *testing.T) {
+	three := 3
+	type test struct {
+		name    string
+		path    string
+		raw     string
+		want    func(c *overrideConfig)
+		wantErr any
+	}
+	tests := []test{
+		{name: "string", path: "Database.Host", raw: "db.local", want: func(c *overrideConfig) { c.Database.Host = "db.local" }},
+		{name: "uint", path: "Database.Port", raw: "5432", want: func(c *overrideConfig) { c.Database.Port = 5432 }},
+		{name: "uint overflow", path: "Database.Port", raw: "70000", wantErr: new(*goval.ConversionError)},
+		{name: "duration", path: "Database.Timeout", raw: "1m30s", want: func(c *overrideConfig) { c.Database.Timeout = 90 * time.Second }},
+		{name: "float", path: "Database.Ratio", raw: "0.5", want: func(c *overrideConfig) { c.Database.Ratio = 0.5 }},
+		{name: "bool", path: "Features[2].Enabled", raw: "true", want: func(c *overrideConfig) { c.Features[2].Enabled = true }},
+		{name: "invalid bool", path: "Features[2].Enabled", raw: "yes please", wantErr: new(*goval.ConversionError)},
+		{name: "named int", path: "Level", raw: "0x10", want: func(c *overrideConfig) { c.Level = 16 }},
+		{name: "leading zeros", path: "Level", raw: "010", want: func(c *overrideConfig) { c.Level = 10 }},
+		{name: "uint leading zeros", path: "Database.Port", raw: "08080", want: func(c *overrideConfig) { c.Database.Port = 8080 }},
+		{name: "binary prefix", path: "Database.Port", raw: "0b101", want: func(c *overrideConfig) { c.Database.Port = 5 }},
+		{name: "string list", path: "Tags", raw: "a, b,c", want: func(c *overrideConfig) { c.Tags = []string{"a", "b", "c"} }},
+		{name: "empty list", path: "Ports", raw: "", want: func(c *overrideConfig) { c.Ports = []int{} }},
+		{name: "int list", path: "Ports", raw: "80,443", want: func(c *overrideConfig) { c.Ports = []int{80, 443} }},
+		{name: "invalid int list", path: "Ports", raw: "80,http", wantErr: new(*goval.ConversionError)},
+		{name: "time", path: "Deadline", raw: "2024-01-02T03:04:05Z", want: func(c *overrideConfig) { c.Deadline = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }},
+		{name: "pointer", path: "Replicas", raw: "3", want: func(c *overrideConfig) { c.Replicas = &three }},
+		{name: "map entry", path: "Labels[env]", raw: "prod", want: func(c *overrideConfig) { c.Labels["env"] = "prod" }},
+		{name: "unsupported type", path: "Features[0]", raw: "a", wantErr: new(*goval.ConversionError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := newOverrideConfig()
+			err = goval.TrySetString(target, path, tt.raw, goval.WithCreate())
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TrySetString() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := newOverrideConfig()
+			tt.want(want)
+			if !reflect.DeepEqual(target, want) {
+				t.Errorf("TrySetString() = %+v, want %+v", target, want)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	type test struct {
+		name      string
+		overrides []string
+		opts      []goval.Option
+		want      func(c *overrideConfig)
+		wantErr   any
+	}
+	tests := []test{
+		{
+			name:      "overrides",
+			overrides: []string{"Database.Port = 5432", "Features[2].Enabled=true", " Tags = a,b", "Database.Host=a=b"},
+			want: func(c *overrideConfig) {
+				c.Database.Port = 5432
+				c.Features[2].Enabled = true
+				c.Tags = []string{"a", "b"}
+				c.Database.Host = "a=b"
+			},
+		},
+		{
+			name:      "= in filters and quoted keys",
+			overrides: []string{`Features[?(@.Name == "b")].Enabled=true`, `Labels["a=b"]=c=d`},
+			want: func(c *overrideConfig) {
+				c.Features[1].Enabled = true
+				c.Labels["a=b"] = "c=d"
+			},
+		},
+		{
+			name:      "through nil pointer",
+			overrides: []string{"Backup.Host=b.local"},
+			want: func(c *overrideConfig) {
+				c.Backup = &overrideBackup{Host: "b.local"}
+			},
+		},
+		{name: "missing field", overrides: []string{"Database.User=root"}, want: func(c *overrideConfig) {}},
+		{name: "missing field in strict mode", overrides: []string{"Database.User=root"}, opts: []goval.Option{goval.Strict()}, wantErr: new(*goval.FieldNotFoundError)},
+		{name: "missing =", overrides: []string{"Database.Port"}, wantErr: new(*goval.ParseError)},
+		{name: "invalid path", overrides: []string{"Database..=1"}, wantErr: new(*goval.ParseError)},
+		{name: "invalid value", overrides: []string{"Database.Port=http"}, wantErr: new(*goval.ConversionError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newOverrideConfig()
+			err := goval.ApplyOverrides(target, tt.overrides, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("ApplyOverrides() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := newOverrideConfig()
+			tt.want(want)
+			if !reflect.DeepEqual(target, want) {
+				t.Errorf("ApplyOverrides() = %+v, want %+v", target, want)
+			}
+		})
+	}
+}
@@ -14,11 +14,13 @@ import "regexp"
 // p.Name() // "age"
 // p.Parent().Name() // "person"
 // p.Type() // PathTypeValue
+// p.String() // "person.age"
 type Path interface {
 	Parent() Path
 	Name() string
 	Split() []Path
 	Type() PathType
+	String() string
 }
 
 type PathType int
@@ -31,26 +33,126 @@ const (
 
 // Parse parsing path.
 func Parse(pathStr string) (_ Path, err error) {
-	tokens := strings.Split(pathStr, ".")
+	tokens, err := splitTokens(pathStr)
+	if err != nil {
+		return nil, err
+	}
+	leading := len(tokens) > 2 && tokens[0].str == "" && tokens[1].str == "" // e.g. ..Password
+	if leading {
+		tokens = tokens[1:]
+	}
 	var p Path
-	for _, token := range tokens {
-		p, err = parseToken(p, token)
+	var recursive bool
+	for i, tk := range tokens {
+		// empty token between dots is recursive descent. e.g. foo..Password
+		if tk.str == "" && !recursive && i+1 < len(tokens) && (i > 0 || leading) {
+			recursive = true
+			continue
+		}
+		p, err = parseToken(p, tk.str)
 		if err != nil {
-			return nil, err
+			return nil, &ParseError{Input: pathStr, Offset: tk.offset, Token: tk.str, Err: err}
+		}
+		if recursive {
+			p = &pathRecursive{Path: p}
+			recursive = false
 		}
 	}
 	return p, nil
 }
 
+// token is a part of path string separated by ".".
+type token struct {
+	str    string
+	offset int
+}
+
+// splitTokens split path string by ".", except inside of brackets and quoted keys.
+func splitTokens(pathStr string) ([]token, error) {
+	var tokens []token
+	start := 0
+	for {
+		i, err := indexTopLevel(pathStr, start, '.')
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return append(tokens, token{str: pathStr[start:], offset: start}), nil
+		}
+		tokens = append(tokens, token{str: pathStr[start:i], offset: start})
+		start = i + 1
+	}
+}
+
+// indexTopLevel returns the index of the first sep in pathStr[start:] outside of brackets and quoted keys,
+// or -1 if there is none.
+func indexTopLevel(pathStr string, start int, sep byte) (int, error) {
+	var depth int
+	var quoted bool
+	for i := start; i < len(pathStr); i++ {
+		c := pathStr[i]
+		switch {
+		case quoted:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth--; depth < 0 {
+				return 0, &ParseError{Input: pathStr, Offset: start, Token: pathStr[start : i+1], Err: errors.New("unbalanced brackets")}
+			}
+		case c == sep && depth == 0:
+			return i, nil
+		}
+	}
+	switch {
+	case quoted:
+		return 0, &ParseError{Input: pathStr, Offset: start, Token: pathStr[start:], Err: errors.New("unterminated quoted key")}
+	case depth != 0:
+		return 0, &ParseError{Input: pathStr, Offset: start, Token: pathStr[start:], Err: errors.New("unbalanced brackets")}
+	}
+	return -1, nil
+}
+
 var regList *regexp.Regexp
+var regRange *regexp.Regexp
+var regUnion *regexp.Regexp
+var regFilter *regexp.Regexp
+var regMap *regexp.Regexp
+var regMapKey *regexp.Regexp
+var regIndex *regexp.Regexp
 var regValue *regexp.Regexp
 
 func init() {
 	var err error
-	if regList, err = regexp.Compile(`(\w+)\[(\d+|\*)?]`); err != nil {
+	if regList, err = regexp.Compile(`^(\w+)\[(-?\d+|\*)?]$`); err != nil {
 		panic(err)
 	}
-	if regValue, err = regexp.Compile(`\w+`); err != nil {
+	if regRange, err = regexp.Compile(`^(\w+)\[(-?\d*):(-?\d*)(?::(-?\d*))?]$`); err != nil {
+		panic(err)
+	}
+	if regUnion, err = regexp.Compile(`^(\w+)\[(-?\d+(?:\s*,\s*-?\d+)+)]$`); err != nil {
+		panic(err)
+	}
+	if regFilter, err = regexp.Compile(`^(\w+)\[\?\((.*)\)]$`); err != nil {
+		panic(err)
+	}
+	if regMap, err = regexp.Compile(`^(\w+)\[(.+)]$`); err != nil {
+		panic(err)
+	}
+	if regMapKey, err = regexp.Compile(`^[\w-]+$`); err != nil {
+		panic(err)
+	}
+	if regIndex, err = regexp.Compile(`^\d+$`); err != nil {
+		panic(err)
+	}
+	if regValue, err = regexp.Compile(`^\w+$`); err != nil {
 		panic(err)
 	}
 }
@@ -85,6 +187,94 @@ func parseToken(parent Path, str string) (Path, error) {
 				index: int(i),
 			}, nil
 		}
+	case regRange.MatchString(str): // match range path. e.g. foo.bar[1:3], foo.bar[::2]
+		group := regRange.FindStringSubmatch(str)
+		p := &pathListRange{
+			path: path{
+				parent: parent,
+				name:   group[1],
+				ptype:  PathTypeCollection,
+			},
+			step: 1,
+		}
+		var err error
+		if p.start, p.hasStart, err = parseOptionalInt(group[2]); err != nil {
+			return nil, err
+		}
+		if p.end, p.hasEnd, err = parseOptionalInt(group[3]); err != nil {
+			return nil, err
+		}
+		if step, ok, err := parseOptionalInt(group[4]); err != nil {
+			return nil, err
+		} else if ok {
+			p.step = step
+		}
+		if p.step == 0 {
+			return nil, errors.New("slice step cannot be zero")
+		}
+		return p, nil
+	case regUnion.MatchString(str): // match union path. e.g. foo.bar[0,2,5]
+		group := regUnion.FindStringSubmatch(str)
+		p := &pathListUnion{
+			path: path{
+				parent: parent,
+				name:   group[1],
+				ptype:  PathTypeCollection,
+			},
+		}
+		for _, idxStr := range strings.Split(group[2], ",") {
+			i, err := strconv.ParseInt(strings.TrimSpace(idxStr), 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			p.indexes = append(p.indexes, int(i))
+		}
+		return p, nil
+	case str == "*": // match all fields path. e.g. foo.*
+		return &pathFieldAll{
+			path: path{
+				parent: parent,
+				name:   str,
+				ptype:  PathTypeCollection,
+			},
+		}, nil
+	case regFilter.MatchString(str): // match filter path. e.g. foo.bar[?(@.Age > 30)]
+		group := regFilter.FindStringSubmatch(str)
+		name := group[1]
+		filter, err := parseFilter(group[2])
+		if err != nil {
+			return nil, err
+		}
+		return &pathFilter{
+			path: path{
+				parent: parent,
+				name:   name,
+				ptype:  PathTypeCollection,
+			},
+			filter: filter,
+			src:    group[2],
+		}, nil
+	case regMap.MatchString(str): // match map key path. e.g. foo.bar[key], foo.bar["my.key"]
+		group := regMap.FindStringSubmatch(str)
+		name := group[1]
+		key := group[2]
+		if strings.HasPrefix(key, `"`) {
+			k, err := strconv.Unquote(key)
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		} else if !regMapKey.MatchString(key) {
+			return nil, errors.New("invalid map key")
+		}
+		return &pathMapKey{
+			path: path{
+				parent: parent,
+				name:   name,
+				ptype:  PathTypeValue,
+			},
+			key: key,
+		}, nil
 	case regValue.MatchString(str): // match value path. e.g. foo.Name
 		return &path{
 			parent: parent,
@@ -93,7 +283,7 @@ func parseToken(parent Path, str string) (Path, error) {
 		}, nil
 	}
 
-	return nil, errors.New("invalid defined path")
+	return nil, errors.New("unexpected token")
 }
 
 type path struct {
@@ -118,6 +308,14 @@ func (p *path) Type() PathType {
 	return p.ptype
 }
 
+func (p *path) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *path) segment() string {
+	return p.name
+}
+
 type pathList struct {
 	path
 	index int
@@ -127,6 +325,14 @@ func (p *pathList) Split() []Path {
 	return splitPath(p)
 }
 
+func (p *pathList) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathList) segment() string {
+	return p.name + "[" + strconv.Itoa(p.index) + "]"
+}
+
 type pathListAll struct {
 	path
 	all bool
@@ -136,9 +342,238 @@ func (p *pathListAll) Split() []Path {
 	return splitPath(p)
 }
 
+func (p *pathListAll) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathListAll) segment() string {
+	return p.name + "[*]"
+}
+
+// pathListRange matches the elements in the range, like python slices. e.g. foo[1:3], foo[::2], foo[-2:]
+type pathListRange struct {
+	path
+	start, end       int
+	hasStart, hasEnd bool
+	step             int
+}
+
+func (p *pathListRange) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathListRange) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathListRange) segment() string {
+	var start, end string
+	if p.hasStart {
+		start = strconv.Itoa(p.start)
+	}
+	if p.hasEnd {
+		end = strconv.Itoa(p.end)
+	}
+	if p.step == 1 {
+		return p.name + "[" + start + ":" + end + "]"
+	}
+	return p.name + "[" + start + ":" + end + ":" + strconv.Itoa(p.step) + "]"
+}
+
+// indexes returns the indexes in the range for the collection of the length.
+func (p *pathListRange) indexes(length int) []int {
+	// clamp converts the negative index from the end, and limits it within [lower, upper].
+	clamp := func(i, lower, upper int) int {
+		if i < 0 {
+			i += length
+		}
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+	var indexes []int
+	if p.step > 0 {
+		start, end := 0, length
+		if p.hasStart {
+			start = clamp(p.start, 0, length)
+		}
+		if p.hasEnd {
+			end = clamp(p.end, 0, length)
+		}
+		for i := start; i < end; i += p.step {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	start, end := length-1, -1
+	if p.hasStart {
+		start = clamp(p.start, -1, length-1)
+	}
+	if p.hasEnd {
+		end = clamp(p.end, -1, length-1)
+	}
+	for i := start; i > end; i += p.step {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// pathListUnion matches the elements at the indexes. e.g. foo[0,2,5]
+type pathListUnion struct {
+	path
+	indexes []int
+}
+
+func (p *pathListUnion) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathListUnion) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathListUnion) segment() string {
+	s := make([]string, len(p.indexes))
+	for i, index := range p.indexes {
+		s[i] = strconv.Itoa(index)
+	}
+	return p.name + "[" + strings.Join(s, ",") + "]"
+}
+
+type pathMapKey struct {
+	path
+	key string
+}
+
+func (p *pathMapKey) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathMapKey) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathMapKey) segment() string {
+	return p.name + "[" + quoteKey(p.key) + "]"
+}
+
+type pathFilter struct {
+	path
+	filter filterExpr
+	src    string
+}
+
+func (p *pathFilter) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathFilter) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathFilter) segment() string {
+	return p.name + "[?(" + p.src + ")]"
+}
+
+// pathFieldAll matches all exported fields of the struct.
+type pathFieldAll struct {
+	path
+}
+
+func (p *pathFieldAll) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathFieldAll) String() string {
+	return joinPath(p.parent, p.segment())
+}
+
+func (p *pathFieldAll) segment() string {
+	return p.name
+}
+
+// pathRecursive searches the path segment in the target and all nested values.
+type pathRecursive struct {
+	Path
+}
+
+func (p *pathRecursive) Split() []Path {
+	return splitPath(p)
+}
+
+func (p *pathRecursive) Type() PathType {
+	return PathTypeCollection
+}
+
+func (p *pathRecursive) String() string {
+	seg := p.Path.(segmenter).segment()
+	if p.Parent() == nil {
+		return ".." + seg
+	}
+	return p.Parent().String() + ".." + seg
+}
+
+// parseOptionalInt parses the integer, or returns false if the string is empty.
+func parseOptionalInt(s string) (int, bool, error) {
+	if s == "" {
+		return 0, false, nil
+	}
+	i, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, false, err
+	}
+	return int(i), true, nil
+}
+
+// segmenter renders a path segment without the parent.
+type segmenter interface {
+	segment() string
+}
+
+// joinPath joins the parent path and the segment string.
+func joinPath(parent Path, seg string) string {
+	if parent == nil {
+		return seg
+	}
+	return parent.String() + "." + seg
+}
+
+// quoteKey quotes the map key, unless it is parsed as the same key without quotes.
+func quoteKey(key string) string {
+	if regMapKey.MatchString(key) && !regIndex.MatchString(strings.TrimPrefix(key, "-")) { // keys such as "-01" would parse as indexes.
+		return key
+	}
+	return strconv.Quote(key)
+}
+
 func splitPath(p Path) []Path {
 	if p.Parent() == nil {
 		return []Path{p}
 	}
 	return append(splitPath(p.Parent()), p)
 }
+
+// TextPath is a Path encoded as text, so that it can be used in configuration files. e.g. JSON, YAML
+type TextPath struct {
+	Path
+}
+
+func (p TextPath) MarshalText() ([]byte, error) {
+	if p.Path == nil {
+		return []byte{}, nil
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *TextPath) UnmarshalText(text []byte) error {
+	path, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	p.Path = path
+	return nil
+}
@@ -3,7 +3,60 @@ package goval
 import "reflect"
 
 type PathInfo struct {
-	RequirePath Path
-	Owner       any //
-	fieldValue  reflect.Value
+	RequirePath  Path
+	ResolvedPath Path                // concrete path of the value. e.g. "Members[3].Name" for "Members[*].Name"
+	Keys         []any               // resolved slice indexes and map keys, in order of the path.
+	Depth        int                 // number of segments of ResolvedPath.
+	Owner        any                 //
+	FieldName    string              // name of the struct field matched the last segment. e.g. "Debug" for "Settings.*"
+	StructField  reflect.StructField // struct field matched the last segment.
+	fieldValue   reflect.Value
+	mapValue     reflect.Value // map owning the value, when the value is a map entry.
+	mapKey       reflect.Value
+	writeBack    []func() // write copied map entries back to the owner maps.
+	readOnly     bool     // true if the value is in the unexported field, which the access policy forbids to write.
+}
+
+// resolve appends the concrete path segment to ResolvedPath.
+func (p *PathInfo) resolve(seg Path, keys ...any) {
+	p.ResolvedPath = seg
+	p.Keys = append(p.Keys[:len(p.Keys):len(p.Keys)], keys...)
+	p.Depth++
+}
+
+// resolveKey replaces the last segment of ResolvedPath with the indexed or keyed segment.
+func (p *PathInfo) resolveKey(seg Path, key any) {
+	p.ResolvedPath = seg
+	p.Keys = append(p.Keys[:len(p.Keys):len(p.Keys)], key)
+}
+
+// set the given value to the field specified by the path.
+func (p PathInfo) set(v reflect.Value) {
+	if p.mapValue.IsValid() {
+		p.mapValue.SetMapIndex(p.mapKey, v)
+	} else {
+		p.fieldValue.Set(v)
+	}
+	for i := len(p.writeBack) - 1; i >= 0; i-- {
+		p.writeBack[i]()
+	}
+}
+
+// canSet reports whether the field specified by the path can be updated.
+func (p PathInfo) canSet() bool {
+	if p.readOnly {
+		return false
+	}
+	if p.mapValue.IsValid() {
+		return p.mapValue.CanInterface()
+	}
+	return p.fieldValue.CanSet()
+}
+
+// setError returns the error explaining why the field can not be updated.
+func (p PathInfo) setError(t reflect.Type) error {
+	if p.readOnly {
+		return &UnexportedFieldError{Path: p.ResolvedPath, Type: t, Write: true}
+	}
+	return &UnsettableFieldError{Path: p.ResolvedPath, Type: t}
 }
@@ -0,0 +1,127 @@
+package goval_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+func TestParse(t *testing.T) {
+	type test struct {
+		name       string
+		path       string
+		wantErr    bool
+		wantOffset int
+		wantToken  string
+	}
+	tests := []test{
+		{name: "value path", path: "Foo.Bar"},
+		{name: "indexed path", path: "Foo[0].Bar"},
+		{name: "all index path", path: "Foo[*].Bar"},
+		{name: "map key path", path: "Foo[key].Bar"},
+		{name: "quoted map key path", path: `Foo["my.key"].Bar`},
+		{name: "recursive path", path: "Foo..Bar"},
+		{name: "leading recursive path", path: "..Bar[*].Baz"},
+		{name: "negative index path", path: "Foo[-1].Bar"},
+		{name: "range path", path: "Foo[1:3].Bar"},
+		{name: "union path", path: "Foo[0,2,5].Bar"},
+		{name: "zero step range", path: "Foo.Bar[::0]", wantErr: true, wantOffset: 4, wantToken: "Bar[::0]"},
+		{name: "empty token", path: "Foo...Bar", wantErr: true, wantOffset: 5, wantToken: ""},
+		{name: "trailing dot", path: "Foo.", wantErr: true, wantOffset: 4, wantToken: ""},
+		{name: "invalid token", path: "Foo.B-r", wantErr: true, wantOffset: 4, wantToken: "B-r"},
+		{name: "invalid map key", path: "Foo.Bar[a b]", wantErr: true, wantOffset: 4, wantToken: "Bar[a b]"},
+		{name: "unbalanced brackets", path: "Foo.Bar[0", wantErr: true, wantOffset: 4, wantToken: "Bar[0"},
+		{name: "unterminated quoted key", path: `Foo["bar].Baz`, wantErr: true, wantOffset: 0, wantToken: `Foo["bar].Baz`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := goval.Parse(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+			var parseErr *goval.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%v) error = %T, want *goval.ParseError", tt.path, err)
+			}
+			if parseErr.Offset != tt.wantOffset || parseErr.Token != tt.wantToken {
+				t.Errorf("Parse(%v) error at %d %q, want at %d %q",
+					tt.path, parseErr.Offset, parseErr.Token, tt.wantOffset, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestPathString(t *testing.T) {
+	type test struct {
+		name string
+		path string
+		want string
+	}
+	tests := []test{
+		{name: "value path", path: "Foo.Bar", want: "Foo.Bar"},
+		{name: "indexed path", path: "Foo[0].Bar", want: "Foo[0].Bar"},
+		{name: "all index path", path: "Foo[].Bar", want: "Foo[*].Bar"},
+		{name: "map key path", path: "Foo[key].Bar", want: "Foo[key].Bar"},
+		{name: "quoted map key path", path: `Foo["my.key"].Bar`, want: `Foo["my.key"].Bar`},
+		{name: "unnecessary quoted map key path", path: `Foo["key"]`, want: `Foo[key]`},
+		{name: "numeric map key path", path: `Foo["404"]`, want: `Foo["404"]`},
+		{name: "negative numeric map key path", path: `Foo["-01"]`, want: `Foo["-01"]`},
+		{name: "filter path", path: `Foo[?(@.Age > 30 && @.Role == "admin")].Bar`, want: `Foo[?(@.Age > 30 && @.Role == "admin")].Bar`},
+		{name: "field wildcard path", path: "Foo.*.Bar", want: "Foo.*.Bar"},
+		{name: "recursive path", path: "Foo..Bar[0].Baz", want: "Foo..Bar[0].Baz"},
+		{name: "leading recursive path", path: "..Bar", want: "..Bar"},
+		{name: "negative index path", path: "Foo[-1].Bar", want: "Foo[-1].Bar"},
+		{name: "range path", path: "Foo[1:3]", want: "Foo[1:3]"},
+		{name: "open range path", path: "Foo[-2:]", want: "Foo[-2:]"},
+		{name: "step range path", path: "Foo[::2]", want: "Foo[::2]"},
+		{name: "union path", path: "Foo[0, 2,5]", want: "Foo[0,2,5]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.String(); got != tt.want {
+				t.Errorf("Parse(%v).String() = %v, want %v", tt.path, got, tt.want)
+			}
+			p2, err := goval.Parse(p.String())
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", p.String(), err)
+			}
+			if !reflect.DeepEqual(p2.String(), p.String()) {
+				t.Errorf("Parse(%v).String() = %v, want %v", p.String(), p2.String(), p.String())
+			}
+		})
+	}
+}
+
+func TestTextPath(t *testing.T) {
+	type config struct {
+		Redact []goval.TextPath `json:"redact"`
+	}
+	var cfg config
+	if err := json.Unmarshal([]byte(`{"redact":["..Password","Members[*].Token"]}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Redact[1].Split()[0].Name(); got != "Members" {
+		t.Errorf("UnmarshalText() = %v, want Members", got)
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"redact":["..Password","Members[*].Token"]}`; got != want {
+		t.Errorf("MarshalText() = %v, want %v", got, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"redact":["Members[*"]}`), &cfg); err == nil {
+		t.Errorf("UnmarshalText() error = nil, want error")
+	}
+}
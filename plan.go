@@ -0,0 +1,280 @@
+package goval
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Plan is a path compiled against the root type T.
+// It resolves the fields by the indexes computed at compile time, instead of searching the field names.
+//
+// Paths including filters, ranges, unions, field wildcards, recursive descents or interface fields can not be resolved statically,
+// so the plan executes them the same way as Each.
+type Plan[T any] struct {
+	path    Path
+	steps   []planStep
+	dynamic bool
+}
+
+type stepKind int
+
+const (
+	_ stepKind = iota
+	stepField
+	stepIndex
+	stepAll
+	stepKey
+	stepAllKeys
+)
+
+type planStep struct {
+	seg   Path
+	kind  stepKind
+	field reflect.StructField
+	index int
+	key   reflect.Value
+}
+
+type planKey struct {
+	typ  reflect.Type
+	path string
+}
+
+var planCache sync.Map // planKey -> *Plan[T]
+
+// Compile compiles the path against the root type T. compiled plans are cached per type and path.
+// InvalidTargetError is returned if T is a pointer type.
+func Compile[T any](path Path) (*Plan[T], error) {
+	key := planKey{typ: typeOf[T](), path: path.String()}
+	if p, ok := planCache.Load(key); ok {
+		return p.(*Plan[T]), nil
+	}
+	p, err := compile[T](path)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := planCache.LoadOrStore(key, p)
+	return actual.(*Plan[T]), nil
+}
+
+// MustCompile is like Compile but panics if the path can not be compiled.
+func MustCompile[T any](path Path) *Plan[T] {
+	p, err := Compile[T](path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func compile[T any](path Path) (*Plan[T], error) {
+	p := &Plan[T]{path: path}
+	t := typeOf[T]()
+	if t.Kind() == reflect.Ptr {
+		return nil, &InvalidTargetError{Path: path, Type: t} // the plans run on *T, so T must not be a pointer.
+	}
+	for _, seg := range path.Split() {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch seg.(type) {
+		case *pathFilter, *pathFieldAll, *pathRecursive, *pathListRange, *pathListUnion:
+			p.dynamic = true
+		}
+		if t.Kind() == reflect.Interface {
+			p.dynamic = true
+		}
+		if p.dynamic {
+			p.steps = nil
+			return p, nil
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, &InvalidTargetError{Path: seg, Type: t}
+		}
+		sf, ok := t.FieldByName(seg.Name())
+		if !ok {
+			return nil, &FieldNotFoundError{Path: seg, Type: t}
+		}
+		step := planStep{seg: seg, kind: stepField, field: sf}
+		t = sf.Type
+
+		var keyStr string
+		switch s := seg.(type) {
+		case *pathList:
+			step.kind = stepIndex
+			step.index = s.index
+			keyStr = strconv.Itoa(s.index)
+		case *pathListAll:
+			step.kind = stepAll
+		case *pathMapKey:
+			step.kind = stepKey
+			keyStr = s.key
+		}
+		switch {
+		case step.kind == stepField:
+		case t.Kind() == reflect.Map:
+			switch step.kind {
+			case stepAll:
+				step.kind = stepAllKeys
+			default:
+				key, err := mapKey(t.Key(), keyStr)
+				if err != nil {
+					return nil, &TypeMismatchError{Path: seg, Want: t.Key(), Got: reflect.TypeOf(keyStr)}
+				}
+				step.kind = stepKey
+				step.key = key
+			}
+			t = t.Elem()
+		case step.kind != stepKey && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+			t = t.Elem()
+		default:
+			return nil, &InvalidTargetError{Path: seg, Type: t}
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// Path returns the compiled path.
+func (p *Plan[T]) Path() Path {
+	return p.path
+}
+
+// Each executes the given function once for each field specified in the path.
+func (p *Plan[T]) Each(target *T, fn funcEach) error {
+	return p.each(target, func(v any, pathInfo PathInfo) error {
+		fn(v, pathInfo)
+		return nil
+	})
+}
+
+// Get returns the field values specified in the path.
+func (p *Plan[T]) Get(target *T) []any {
+	s := make([]any, 0)
+	_ = p.each(target, func(v any, _ PathInfo) error {
+		s = append(s, v)
+		return nil
+	})
+	return s
+}
+
+// Set updates the fields specified in the path with the given value, the same way as TrySet.
+func (p *Plan[T]) Set(target *T, newValue any) error {
+	return p.each(target, func(_ any, pathInfo PathInfo) error {
+		return assign(pathInfo, newValue)
+	})
+}
+
+func (p *Plan[T]) each(target *T, fn funcEachE) error {
+	if p.dynamic {
+		return tryEach(target, p.path, fn, nil)
+	}
+	pathInfo := PathInfo{
+		RequirePath: p.path,
+	}
+	return p.run(reflect.ValueOf(target), p.steps, pathInfo, fn)
+}
+
+// run executes the steps on the target pointer.
+func (p *Plan[T]) run(target reflect.Value, steps []planStep, pathInfo PathInfo, fn funcEachE) error {
+	if target.IsNil() {
+		return nil
+	}
+	st := steps[0]
+	fv, err := target.Elem().FieldByIndexErr(st.field.Index)
+	if err != nil {
+		return nil // nil embedded pointer
+	}
+	pathInfo.Owner = target.Interface()
+	pathInfo.FieldName = st.field.Name
+	pathInfo.StructField = st.field
+	pathInfo.fieldValue = fv
+	pathInfo.mapValue = reflect.Value{}
+	pathInfo.mapKey = reflect.Value{}
+
+	switch st.kind {
+	case stepIndex:
+		return p.runIndex(fv, st, st.index, steps, pathInfo, fn)
+	case stepAll:
+		for i := 0; i < fv.Len(); i++ {
+			if err := p.runIndex(fv, st, i, steps, pathInfo, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case stepKey:
+		return p.runKey(fv, st, st.key, steps, pathInfo, fn)
+	case stepAllKeys:
+		for _, key := range sortedMapKeys(fv) {
+			if err := p.runKey(fv, st, key, steps, pathInfo, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pathInfo.resolve(&path{
+		parent: pathInfo.ResolvedPath,
+		name:   st.field.Name,
+		ptype:  PathTypeValue,
+	})
+	return p.next(fv, steps[1:], pathInfo, fn)
+}
+
+func (p *Plan[T]) runIndex(fv reflect.Value, st planStep, index int, steps []planStep, pathInfo PathInfo, fn funcEachE) error {
+	if index < 0 {
+		index += fv.Len()
+	}
+	if index < 0 || index >= fv.Len() {
+		return nil
+	}
+	fv = fv.Index(index)
+	pathInfo.fieldValue = fv
+	pathInfo.resolve(&pathList{
+		path: path{
+			parent: pathInfo.ResolvedPath,
+			name:   st.field.Name,
+			ptype:  PathTypeValue,
+		},
+		index: index,
+	}, index)
+	return p.next(fv, steps[1:], pathInfo, fn)
+}
+
+func (p *Plan[T]) runKey(m reflect.Value, st planStep, key reflect.Value, steps []planStep, pathInfo PathInfo, fn funcEachE) error {
+	v := m.MapIndex(key)
+	if !v.IsValid() {
+		return nil
+	}
+	pathInfo.resolve(&pathMapKey{
+		path: path{
+			parent: pathInfo.ResolvedPath,
+			name:   st.field.Name,
+			ptype:  PathTypeValue,
+		},
+		key: keyString(key),
+	}, keyInterface(key))
+	if len(steps) == 1 {
+		pathInfo.fieldValue = v
+		pathInfo.mapValue = m
+		pathInfo.mapKey = key
+		return fn(fieldValue(v), pathInfo)
+	}
+	if v.Kind() == reflect.Struct {
+		cp := reflect.New(v.Type())
+		cp.Elem().Set(v)
+		pathInfo.writeBack = append(pathInfo.writeBack[:len(pathInfo.writeBack):len(pathInfo.writeBack)], mapWriteBack(m, key, cp))
+		return p.run(cp, steps[1:], pathInfo, fn)
+	}
+	return p.next(v, steps[1:], pathInfo, fn)
+}
+
+// next executes the rest steps on the field value, or the function if no steps left.
+func (p *Plan[T]) next(fv reflect.Value, steps []planStep, pathInfo PathInfo, fn funcEachE) error {
+	if len(steps) == 0 {
+		return fn(fieldValue(fv), pathInfo)
+	}
+	if fv.Kind() == reflect.Ptr {
+		return p.run(fv, steps, pathInfo, fn)
+	}
+	return p.run(fv.Addr(), steps, pathInfo, fn)
+}
@@ -0,0 +1,152 @@
+package goval_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+type planMember struct {
+	Name string
+	Age  int
+}
+
+type planTeam struct {
+	Name    string
+	Leader  *planMember
+	Members []*planMember
+	Labels  map[string]string
+	Groups  map[string]planMember
+	Extra   any
+}
+
+func newPlanTeam() *planTeam {
+	return &planTeam{
+		Name:   "TEAM-A",
+		Leader: &planMember{Name: "Alice", Age: 25},
+		Members: []*planMember{
+			{Name: "Alice", Age: 25},
+			{Name: "Bob", Age: 40},
+		},
+		Labels: map[string]string{"env": "prod"},
+		Groups: map[string]planMember{"dev": {Name: "Carol"}},
+		Extra:  &planMember{Name: "Dave"},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	type test struct {
+		name    string
+		path    string
+		want    []any
+		wantErr any
+	}
+	tests := []test{
+		{name: "field", path: "Name", want: []any{"TEAM-A"}},
+		{name: "pointer field", path: "Leader.Name", want: []any{"Alice"}},
+		{name: "indexed field", path: "Members[1].Name", want: []any{"Bob"}},
+		{name: "all fields", path: "Members[*].Age", want: []any{25, 40}},
+		{name: "map key", path: "Labels[env]", want: []any{"prod"}},
+		{name: "map struct field", path: "Groups[*].Name", want: []any{"Carol"}},
+		{name: "dynamic filter", path: "Members[?(@.Age > 30)].Name", want: []any{"Bob"}},
+		{name: "dynamic interface", path: "Extra.Name", want: []any{"Dave"}},
+		{name: "missing field", path: "Members[*].Email", wantErr: new(*goval.FieldNotFoundError)},
+		{name: "index on field", path: "Name[0]", wantErr: new(*goval.InvalidTargetError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := goval.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := goval.Compile[planTeam](path)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("Compile(%v) error = %v, want %T", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile(%v) error = %v", tt.path, err)
+			}
+			target := newPlanTeam()
+			if got := plan.Get(target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan(%v).Get() = %v, want %v", tt.path, got, tt.want)
+			}
+			if got := goval.GetAll[any](target, path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+			cached, _ := goval.Compile[planTeam](path)
+			if cached != plan {
+				t.Errorf("Compile(%v) is not cached", tt.path)
+			}
+		})
+	}
+
+	t.Run("pointer root type", func(t *testing.T) {
+		path, _ := goval.Parse("Name")
+		if _, err := goval.Compile[*planTeam](path); !errors.As(err, new(*goval.InvalidTargetError)) {
+			t.Errorf("Compile() error = %v, want %T", err, new(*goval.InvalidTargetError))
+		}
+	})
+}
+
+func TestPlanSet(t *testing.T) {
+	target := newPlanTeam()
+	path, _ := goval.Parse("Groups[dev].Name")
+	plan := goval.MustCompile[planTeam](path)
+	if err := plan.Set(target, "Eve"); err != nil {
+		t.Fatal(err)
+	}
+	if got := target.Groups["dev"].Name; got != "Eve" {
+		t.Errorf("Plan(%v).Set() = %v, want Eve", path, got)
+	}
+	var mismatch *goval.TypeMismatchError
+	if err := plan.Set(target, 1); !errors.As(err, &mismatch) {
+		t.Errorf("Plan(%v).Set() error = %v, want %T", path, err, mismatch)
+	}
+
+	path, _ = goval.Parse("Leader")
+	plan = goval.MustCompile[planTeam](path)
+	leader := target.Leader
+	if err := plan.Set(target, planMember{Name: "Zed"}); err != nil {
+		t.Fatal(err)
+	}
+	if target.Leader != leader || leader.Name != "Zed" {
+		t.Errorf("Plan(%v).Set() = %+v, want Zed in the same pointer", path, target.Leader)
+	}
+	if err := plan.Set(target, &planMember{Name: "Yui"}); err != nil || target.Leader.Name != "Yui" {
+		t.Errorf("Plan(%v).Set() = %+v, %v, want Yui", path, target.Leader, err)
+	}
+
+	path, _ = goval.Parse("Members[*].Name")
+	plan = goval.MustCompile[planTeam](path)
+	var resolved []string
+	_ = plan.Each(target, func(v any, pathInfo goval.PathInfo) {
+		resolved = append(resolved, pathInfo.ResolvedPath.String())
+	})
+	if want := []string{"Members[0].Name", "Members[1].Name"}; !reflect.DeepEqual(resolved, want) {
+		t.Errorf("Plan(%v).Each() ResolvedPath = %v, want %v", path, resolved, want)
+	}
+}
+
+func BenchmarkGetAll(b *testing.B) {
+	target := newPlanTeam()
+	path, _ := goval.Parse("Members[*].Name")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		goval.GetAll[string](target, path)
+	}
+}
+
+func BenchmarkPlanGet(b *testing.B) {
+	target := newPlanTeam()
+	path, _ := goval.Parse("Members[*].Name")
+	plan := goval.MustCompile[planTeam](path)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plan.Get(target)
+	}
+}
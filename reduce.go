@@ -0,0 +1,121 @@
+package goval
+
+import "reflect"
+
+// Number is a constraint of the numeric types accepted by the aggregate functions.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Reduce executes the reducer function for each field specified in the path, and returns the accumulated value.
+func Reduce[T, A any](target any, path Path, init A, fn func(acc A, v T, info PathInfo) A, opts ...Option) A {
+	acc, err := TryReduce(target, path, init, fn, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return acc
+}
+
+// TryReduce is like Reduce but returns an error instead of panicking.
+func TryReduce[T, A any](target any, path Path, init A, fn func(acc A, v T, info PathInfo) A, opts ...Option) (A, error) {
+	acc := init
+	err := tryEach(target, path, func(v any, pathInfo PathInfo) error {
+		r, err := as[T](v, pathInfo)
+		if err != nil {
+			return err
+		}
+		acc = fn(acc, r, pathInfo)
+		return nil
+	}, opts)
+	if err != nil {
+		return init, err
+	}
+	return acc, nil
+}
+
+// Sum returns the sum of the numeric fields specified in the path.
+func Sum[T Number](target any, path Path, opts ...Option) T {
+	return Reduce(target, path, 0, func(acc T, v any, _ PathInfo) T {
+		return acc + number[T](v, path)
+	}, opts...)
+}
+
+// Min returns the minimum of the numeric fields specified in the path, false if no field matched.
+func Min[T Number](target any, path Path, opts ...Option) (T, bool) {
+	return minMax[T](target, path, func(a, b T) bool { return a < b }, opts)
+}
+
+// Max returns the maximum of the numeric fields specified in the path, false if no field matched.
+func Max[T Number](target any, path Path, opts ...Option) (T, bool) {
+	return minMax[T](target, path, func(a, b T) bool { return a > b }, opts)
+}
+
+func minMax[T Number](target any, path Path, better func(a, b T) bool, opts []Option) (T, bool) {
+	var r T
+	var found bool
+	Each(target, path, func(v any, _ PathInfo) {
+		n := number[T](v, path)
+		if !found || better(n, r) {
+			r = n
+		}
+		found = true
+	}, opts...)
+	return r, found
+}
+
+// Avg returns the average of the numeric fields specified in the path, false if no field matched.
+// The fields are summed as float64, whatever their types are.
+func Avg(target any, path Path, opts ...Option) (float64, bool) {
+	var sum float64
+	var n int
+	Each(target, path, func(v any, _ PathInfo) {
+		sum += number[float64](v, path)
+		n++
+	}, opts...)
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// Count returns the number of the fields specified in the path.
+func Count(target any, path Path, opts ...Option) int {
+	var n int
+	Each(target, path, func(_ any, _ PathInfo) {
+		n++
+	}, opts...)
+	return n
+}
+
+// number converts the numeric value to T, through the basic type normalized by fieldValueAny.
+func number[T Number](v any, path Path) T {
+	switch n := fieldValueAny(reflect.ValueOf(v)).(type) {
+	case int:
+		return T(n)
+	case int8:
+		return T(n)
+	case int16:
+		return T(n)
+	case int32:
+		return T(n)
+	case int64:
+		return T(n)
+	case uint:
+		return T(n)
+	case uint8:
+		return T(n)
+	case uint16:
+		return T(n)
+	case uint32:
+		return T(n)
+	case uint64:
+		return T(n)
+	case float32:
+		return T(n)
+	case float64:
+		return T(n)
+	}
+	panic(&TypeMismatchError{Path: path, Want: typeOf[T](), Got: reflect.TypeOf(v)})
+}
@@ -0,0 +1,135 @@
+package goval_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tadjp/goval"
+)
+
+func TestAggregate(t *testing.T) {
+	type member struct {
+		Name  string
+		Age   int
+		Score float32
+	}
+	type team struct {
+		Members []member
+	}
+
+	type want struct {
+		sum   float64
+		min   float64
+		max   float64
+		avg   float64
+		count int
+		found bool
+	}
+	type test struct {
+		name   string
+		target any
+		path   string
+		want   want
+	}
+
+	// create basic test data
+	defaultTest := func(fn func(tt test) test) test {
+		tt := test{
+			target: &team{
+				Members: []member{
+					{Name: "Alice", Age: 25, Score: 1.5},
+					{Name: "Bob", Age: 40, Score: 3},
+					{Name: "Carol", Age: 31, Score: 0.5},
+				},
+			},
+		}
+		return fn(tt)
+	}
+
+	tests := []test{
+		defaultTest(func(tt test) test {
+			tt.name = "int fields"
+			tt.path = "Members[*].Age"
+			tt.want = want{sum: 96, min: 25, max: 40, avg: 32, count: 3, found: true}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "float fields"
+			tt.path = "Members[*].Score"
+			tt.want = want{sum: 5, min: 0.5, max: 3, avg: 5.0 / 3, count: 3, found: true}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "fractional fields"
+			tt.target = &team{Members: []member{{Score: 1.5}, {Score: 2.5}}}
+			tt.path = "Members[*].Score"
+			tt.want = want{sum: 4, min: 1.5, max: 2.5, avg: 2, count: 2, found: true}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "single field"
+			tt.path = "Members[1].Age"
+			tt.want = want{sum: 40, min: 40, max: 40, avg: 40, count: 1, found: true}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "no fields"
+			tt.target = &team{}
+			tt.path = "Members[*].Age"
+			tt.want = want{}
+			return tt
+		}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.path)
+			if got := goval.Sum[float64](tt.target, path); got != tt.want.sum {
+				t.Errorf("Sum(%v) = %v, want %v", tt.path, got, tt.want.sum)
+			}
+			if got, found := goval.Min[float64](tt.target, path); got != tt.want.min || found != tt.want.found {
+				t.Errorf("Min(%v) = %v, %v, want %v, %v", tt.path, got, found, tt.want.min, tt.want.found)
+			}
+			if got, found := goval.Max[float64](tt.target, path); got != tt.want.max || found != tt.want.found {
+				t.Errorf("Max(%v) = %v, %v, want %v, %v", tt.path, got, found, tt.want.max, tt.want.found)
+			}
+			if got, found := goval.Avg(tt.target, path); got != tt.want.avg || found != tt.want.found {
+				t.Errorf("Avg(%v) = %v, %v, want %v, %v", tt.path, got, found, tt.want.avg, tt.want.found)
+			}
+			if got := goval.Count(tt.target, path); got != tt.want.count {
+				t.Errorf("Count(%v) = %v, want %v", tt.path, got, tt.want.count)
+			}
+		})
+	}
+}
+
+func ExampleReduce() {
+	type Member struct {
+		Name string
+		Age  int
+	}
+	type Team struct {
+		Members []*Member
+	}
+	team := Team{
+		Members: []*Member{
+			{Name: "Alice", Age: 25},
+			{Name: "Bob", Age: 40},
+		},
+	}
+	path, _ := goval.Parse("Members[*].Name")
+	names := goval.Reduce(&team, path, "", func(acc string, v string, info goval.PathInfo) string {
+		if acc == "" {
+			return v
+		}
+		return acc + ", " + v
+	})
+	fmt.Println(names) // Alice, Bob
+
+	path, _ = goval.Parse("Members[*].Age")
+	fmt.Println(goval.Sum[int](&team, path)) // 65
+	fmt.Println(goval.Max[int](&team, path)) // 40 true
+	// Output:
+	// Alice, Bob
+	// 65
+	// 40 true
+}
@@ -1,24 +1,104 @@
 package goval
 
 import (
+	"errors"
 	"reflect"
 )
 
-//Set Update the structure field with a given value.
-func Set[T any](target any, path Path, newValue T) {
-	SetFunc(target, path, func(_ T, _ PathInfo) T {
-		return newValue
-	})
+// Set Update the structure field with a given value.
+func Set[T any](target any, path Path, newValue T, opts ...Option) {
+	if err := TrySet(target, path, newValue, opts...); err != nil {
+		panic(err)
+	}
 }
 
 // SetFunc Update the structure field with a function value.
-func SetFunc[T any](target any, path Path, fn func(v T, pathInfo PathInfo) T) {
-	refTarget := reflect.ValueOf(target) // *interface{}
-	pathInfo := PathInfo{
-		RequirePath: path,
-	}
-	each(refTarget, path.Split(), pathInfo, func(v any, pathInfo PathInfo) {
-		newVal := fn(v.(T), pathInfo)
-		pathInfo.fieldValue.Set(reflect.ValueOf(newVal))
-	})
+func SetFunc[T any](target any, path Path, fn func(v T, pathInfo PathInfo) T, opts ...Option) {
+	if err := TrySetFunc(target, path, fn, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// TrySet is like Set but returns an error instead of panicking.
+func TrySet[T any](target any, path Path, newValue T, opts ...Option) error {
+	return tryWrite(target, path, func(_ any, pathInfo PathInfo) error {
+		return assign(pathInfo, newValue)
+	}, opts)
+}
+
+// TrySetFunc is like SetFunc but returns an error instead of panicking.
+func TrySetFunc[T any](target any, path Path, fn func(v T, pathInfo PathInfo) T, opts ...Option) error {
+	return tryWrite(target, path, func(v any, pathInfo PathInfo) error {
+		cur, err := as[T](v, pathInfo)
+		if err != nil {
+			return err
+		}
+		if !pathInfo.canSet() {
+			return pathInfo.setError(pathInfo.fieldValue.Type())
+		}
+		return assign(pathInfo, fn(cur, pathInfo))
+	}, opts)
+}
+
+// SetAny updates the fields with the value converted to the field types.
+// e.g. int to int32 with overflow checks, string to a named string type, a value to a pointer,
+// string to the types implementing encoding.TextUnmarshaler.
+func SetAny(target any, path Path, newValue any, opts ...Option) {
+	if err := TrySetAny(target, path, newValue, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// TrySetAny is like SetAny but returns an error instead of panicking.
+// ConversionError is returned when the value can not be represented by the field type.
+func TrySetAny(target any, path Path, newValue any, opts ...Option) error {
+	return tryWrite(target, path, func(_ any, pathInfo PathInfo) error {
+		fieldType := pathInfo.fieldValue.Type()
+		if !pathInfo.canSet() {
+			return pathInfo.setError(fieldType)
+		}
+		newVal, err := convert(reflect.ValueOf(newValue), fieldType)
+		if errors.Is(err, errNotConvertible) {
+			return &TypeMismatchError{Path: pathInfo.ResolvedPath, Want: fieldType, Got: reflect.TypeOf(newValue)}
+		}
+		if err != nil {
+			return &ConversionError{Path: pathInfo.ResolvedPath, Value: newValue, Type: fieldType, Err: err}
+		}
+		pathInfo.set(newVal)
+		return nil
+	}, opts)
+}
+
+// assign sets the new value to the field specified by the path info.
+func assign(pathInfo PathInfo, newValue any) error {
+	fieldType := pathInfo.fieldValue.Type()
+	if !pathInfo.canSet() {
+		return pathInfo.setError(fieldType)
+	}
+	newVal := reflect.ValueOf(newValue)
+	if rv, ok := newValue.(reflect.Value); ok && !newVal.Type().AssignableTo(fieldType) {
+		newVal = rv // RawValue mode
+	}
+	if newVal.IsValid() && newVal.Kind() == reflect.Ptr && newVal.Type().Elem() == fieldType && !newVal.Type().AssignableTo(fieldType) {
+		newVal = reflect.Indirect(newVal) // ByPointer mode
+	}
+	if !newVal.IsValid() {
+		newVal = reflect.Zero(fieldType)
+	}
+	if fieldType.Kind() == reflect.Ptr && !newVal.Type().AssignableTo(fieldType) && newVal.Type().AssignableTo(fieldType.Elem()) {
+		// ByValue mode passes the pointee, so the value is written to it, or to a new pointer if nil.
+		if pathInfo.fieldValue.IsNil() {
+			p := reflect.New(fieldType.Elem())
+			p.Elem().Set(newVal)
+			pathInfo.set(p)
+			return nil
+		}
+		pathInfo.fieldValue.Elem().Set(newVal)
+		return nil
+	}
+	if !newVal.Type().AssignableTo(fieldType) {
+		return &TypeMismatchError{Path: pathInfo.ResolvedPath, Want: fieldType, Got: newVal.Type()}
+	}
+	pathInfo.set(newVal)
+	return nil
 }
@@ -1,11 +1,15 @@
 package goval_test
 
 import (
+	"errors"
 	"fmt"
 	"github.com/tadjp/goval"
+	"math"
+	"net/netip"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestSetFunc(t *testing.T) {
@@ -69,6 +73,57 @@ func TestSetFunc(t *testing.T) {
 			}
 			return tt
 		}),
+		defaultTest(func(tt test) test {
+			tt.name = "update map field value"
+			type S struct {
+				V map[string]string
+			}
+			tt.args = args{
+				target: &S{
+					V: map[string]string{
+						"a": "foo",
+						"b": "foo",
+					},
+				},
+				path:   "V[a]",
+				newVal: "bar",
+			}
+
+			tt.want = &S{
+				V: map[string]string{
+					"a": "bar",
+					"b": "foo",
+				},
+			}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "update map struct field values"
+			type T struct {
+				Name string
+			}
+			type S struct {
+				V map[string]T
+			}
+			tt.args = args{
+				target: &S{
+					V: map[string]T{
+						"a": {Name: "foo"},
+						"b": {Name: "foo"},
+					},
+				},
+				path:   "V[*].Name",
+				newVal: "bar",
+			}
+
+			tt.want = &S{
+				V: map[string]T{
+					"a": {Name: "bar"},
+					"b": {Name: "bar"},
+				},
+			}
+			return tt
+		}),
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,6 +143,7 @@ func TestSet(t *testing.T) {
 		target any
 		path   string
 		newVal any
+		opts   []goval.Option
 	}
 	type test struct {
 		name string
@@ -117,11 +173,27 @@ func TestSet(t *testing.T) {
 			}
 			return tt
 		}),
+		defaultTest(func(tt test) test {
+			tt.name = "update field by json name"
+			type S struct {
+				CreatedAt string `json:"created_at"`
+			}
+			tt.args = args{
+				target: &S{},
+				path:   "created_at",
+				newVal: "2024",
+				opts:   []goval.Option{goval.WithTagNames("json")},
+			}
+			tt.want = &S{
+				CreatedAt: "2024",
+			}
+			return tt
+		}),
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			path, _ := goval.Parse(tt.args.path)
-			goval.Set(tt.args.target, path, tt.args.newVal)
+			goval.Set(tt.args.target, path, tt.args.newVal, tt.args.opts...)
 			if !reflect.DeepEqual(tt.args.target, tt.want) {
 				t.Errorf("SetFunc() = %v, want %v", tt.args.target, tt.want)
 			}
@@ -129,6 +201,179 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestTrySetFunc(t *testing.T) {
+	type S struct {
+		V   string
+		N   int
+		str string
+	}
+	type args struct {
+		target any
+		path   string
+		newVal any
+	}
+	type test struct {
+		name    string
+		args    args
+		want    any
+		wantErr any
+	}
+	defaultTest := func(fn func(tt test) test) test {
+		tt := test{
+			args: args{
+				target: &S{V: "foo", N: 1, str: "foo"},
+			},
+		}
+		return fn(tt)
+	}
+
+	tests := []test{
+		defaultTest(func(tt test) test {
+			tt.name = "update field value"
+			tt.args.path = "V"
+			tt.args.newVal = "bar"
+			tt.want = &S{V: "bar", N: 1, str: "foo"}
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "assign mismatched type"
+			tt.args.path = "N"
+			tt.args.newVal = "bar"
+			tt.wantErr = new(*goval.TypeMismatchError)
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "update unexported field"
+			tt.args.path = "str"
+			tt.args.newVal = "bar"
+			tt.wantErr = new(*goval.UnsettableFieldError)
+			return tt
+		}),
+		defaultTest(func(tt test) test {
+			tt.name = "non-pointer target"
+			tt.args.target = S{}
+			tt.args.path = "V"
+			tt.args.newVal = "bar"
+			tt.wantErr = new(*goval.InvalidTargetError)
+			return tt
+		}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.args.path)
+			err := goval.TrySetFunc[any](tt.args.target, path, func(v any, pathInfo goval.PathInfo) any {
+				return tt.args.newVal
+			})
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TrySetFunc(%v) error = %v, want %T", tt.args.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("TrySetFunc(%v) error = %v", tt.args.path, err)
+			}
+			if !reflect.DeepEqual(tt.args.target, tt.want) {
+				t.Errorf("TrySetFunc() = %v, want %v", tt.args.target, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetWithCreate(t *testing.T) {
+	type host struct {
+		Host string
+		Tags []string
+	}
+	type database struct {
+		Primary  *host
+		Replicas []*host
+		Shards   map[string]*host
+		Regions  map[string]host
+	}
+	type config struct {
+		Database *database
+		Labels   map[string]string
+	}
+	type test struct {
+		name   string
+		path   string
+		newVal string
+		want   *config
+	}
+	tests := []test{
+		{
+			name:   "nil pointers",
+			path:   "Database.Primary.Host",
+			newVal: "x",
+			want:   &config{Database: &database{Primary: &host{Host: "x"}}},
+		},
+		{
+			name:   "grow slice",
+			path:   "Database.Replicas[1].Host",
+			newVal: "x",
+			want:   &config{Database: &database{Replicas: []*host{nil, {Host: "x"}}}},
+		},
+		{
+			name:   "grow nested slice",
+			path:   "Database.Primary.Tags[2]",
+			newVal: "x",
+			want:   &config{Database: &database{Primary: &host{Tags: []string{"", "", "x"}}}},
+		},
+		{
+			name:   "create map",
+			path:   "Labels[env]",
+			newVal: "prod",
+			want:   &config{Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name:   "create map pointer entry",
+			path:   "Database.Shards[a].Host",
+			newVal: "x",
+			want:   &config{Database: &database{Shards: map[string]*host{"a": {Host: "x"}}}},
+		},
+		{
+			name:   "create map struct entry",
+			path:   "Database.Regions[jp].Host",
+			newVal: "x",
+			want:   &config{Database: &database{Regions: map[string]host{"jp": {Host: "x"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.path)
+			target := &config{}
+			goval.Set(target, path, tt.newVal)
+			if !reflect.DeepEqual(target, &config{}) {
+				t.Errorf("Set(%v) without WithCreate = %v, want no change", tt.path, target)
+			}
+			if got := goval.GetAll[string](target, path, goval.WithCreate()); len(got) != 0 || !reflect.DeepEqual(target, &config{}) {
+				t.Errorf("GetAll(%v) with WithCreate = %v, %+v, want no values and no change", tt.path, got, target)
+			}
+			if goval.Exists(target, path, goval.WithCreate()) {
+				t.Errorf("Exists(%v) with WithCreate = true, want false", tt.path)
+			}
+			goval.Set(target, path, tt.newVal, goval.WithCreate())
+			if !reflect.DeepEqual(target, tt.want) {
+				t.Errorf("Set(%v) = %+v, want %+v", tt.path, target, tt.want)
+			}
+		})
+	}
+
+	t.Run("reads", func(t *testing.T) {
+		target := &config{Database: &database{Replicas: []*host{{Host: "a"}}}, Labels: map[string]string{}}
+		path, _ := goval.Parse("Database.Replicas[3].Host")
+		goval.GetAll[string](target, path, goval.WithCreate())
+		if len(target.Database.Replicas) != 1 {
+			t.Errorf("GetAll() with WithCreate grew the slice to %d", len(target.Database.Replicas))
+		}
+		path, _ = goval.Parse("Labels[x]")
+		if goval.Exists(target, path, goval.WithCreate()) || len(target.Labels) != 0 {
+			t.Errorf("Exists() with WithCreate = true, %v, want false and no entry", target.Labels)
+		}
+	})
+}
+
 func ExampleSet() {
 	type Member struct {
 		Name string
@@ -184,3 +429,273 @@ func ExampleSetFunc() {
 	// alice
 	// bob
 }
+
+func TestAccessPolicy(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type S struct {
+		V     string
+		str   string
+		in    inner
+		ptr   *inner
+		items []string
+	}
+	newS := func() *S {
+		return &S{V: "foo", str: "foo", in: inner{Name: "a"}, items: []string{"x"}}
+	}
+
+	type test struct {
+		name    string
+		path    string
+		policy  []goval.Option
+		want    []any
+		wantErr any
+	}
+	readTests := []test{
+		{name: "read unexported field", path: "str", want: []any{"foo"}},
+		{name: "read unexported struct", path: "in", want: []any{inner{Name: "a"}}},
+		{name: "read field of unexported struct", path: "in.Name", want: []any{"a"}},
+		{name: "read unexported slice", path: "items[0]", want: []any{"x"}},
+		{name: "exported only", path: "in.Name", policy: []goval.Option{goval.WithAccess(goval.ExportedOnly)}, wantErr: new(*goval.UnexportedFieldError)},
+		{name: "exported only exported field", path: "V", policy: []goval.Option{goval.WithAccess(goval.ExportedOnly)}, want: []any{"foo"}},
+	}
+	for _, tt := range readTests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.path)
+			got, err := goval.TryGetAll[any](newS(), path, tt.policy...)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TryGetAll() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TryGetAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	writeTests := []struct {
+		name    string
+		path    string
+		newVal  any
+		opts    []goval.Option
+		want    func(s *S)
+		wantErr any
+	}{
+		{name: "write unexported field", path: "str", newVal: "bar", wantErr: new(*goval.UnexportedFieldError)},
+		{name: "write field of unexported struct", path: "in.Name", newVal: "b", wantErr: new(*goval.UnsettableFieldError)},
+		{name: "create in unexported field", path: "ptr.Name", newVal: "b", opts: []goval.Option{goval.WithCreate()}, want: func(s *S) {}},
+		{
+			name:   "unsafe write unexported field",
+			path:   "str",
+			newVal: "bar",
+			opts:   []goval.Option{goval.WithAccess(goval.UnsafeWriteUnexported)},
+			want:   func(s *S) { s.str = "bar" },
+		},
+		{
+			name:   "unsafe write field of unexported struct",
+			path:   "ptr.Name",
+			newVal: "b",
+			opts:   []goval.Option{goval.WithAccess(goval.UnsafeWriteUnexported), goval.WithCreate()},
+			want:   func(s *S) { s.ptr = &inner{Name: "b"} },
+		},
+	}
+	for _, tt := range writeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.path)
+			target := newS()
+			err := goval.TrySet(target, path, tt.newVal, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TrySet() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := newS()
+			tt.want(want)
+			if !reflect.DeepEqual(target, want) {
+				t.Errorf("TrySet() = %+v, want %+v", target, want)
+			}
+		})
+	}
+
+	t.Run("write through read results", func(t *testing.T) {
+		target := newS()
+		path, _ := goval.Parse("str")
+		*goval.GetAll[*string](target, path, goval.WithValueMode(goval.ByPointer))[0] = "hacked"
+		path, _ = goval.Parse("in.Name")
+		*goval.GetAll[*string](target, path, goval.WithValueMode(goval.ByPointer))[0] = "hacked"
+		if rv := goval.GetAll[reflect.Value](target, path, goval.WithValueMode(goval.RawValue))[0]; rv.CanSet() {
+			t.Error("RawValue of unexported field is settable")
+		}
+		if !reflect.DeepEqual(target, newS()) {
+			t.Errorf("GetAll() results wrote to %+v", target)
+		}
+	})
+}
+
+type convStatus string
+
+func TestSetAny(t *testing.T) {
+	type S struct {
+		I32    int32
+		U8     uint8
+		F32    float32
+		I      int
+		Status convStatus
+		Str    string
+		PStr   *string
+		PPI    **int
+		At     time.Time
+		PAt    *time.Time
+		Addr   netip.Addr
+		Any    any
+	}
+	str := func(s string) *string { return &s }
+	n := 5
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type test struct {
+		name    string
+		path    string
+		newVal  any
+		want    func(s *S)
+		wantErr any
+	}
+	tests := []test{
+		{name: "narrow int", path: "I32", newVal: 42, want: func(s *S) { s.I32 = 42 }},
+		{name: "int overflow", path: "I32", newVal: math.MaxInt64, wantErr: new(*goval.ConversionError)},
+		{name: "negative to uint", path: "U8", newVal: -1, wantErr: new(*goval.ConversionError)},
+		{name: "uint overflow", path: "U8", newVal: uint(256), wantErr: new(*goval.ConversionError)},
+		{name: "int to float", path: "F32", newVal: 3, want: func(s *S) { s.F32 = 3 }},
+		{name: "float overflow", path: "F32", newVal: math.MaxFloat64, wantErr: new(*goval.ConversionError)},
+		{name: "integral float to int", path: "I", newVal: 3.0, want: func(s *S) { s.I = 3 }},
+		{name: "fractional float to int", path: "I", newVal: 3.5, wantErr: new(*goval.ConversionError)},
+		{name: "string to named type", path: "Status", newVal: "active", want: func(s *S) { s.Status = "active" }},
+		{name: "named type to string", path: "Str", newVal: convStatus("active"), want: func(s *S) { s.Str = "active" }},
+		{name: "wrap pointer", path: "PStr", newVal: "x", want: func(s *S) { s.PStr = str("x") }},
+		{name: "wrap pointer to pointer", path: "PPI", newVal: int64(5), want: func(s *S) { p := &n; s.PPI = &p }},
+		{name: "unwrap pointer", path: "Str", newVal: str("x"), want: func(s *S) { s.Str = "x" }},
+		{name: "nil to pointer", path: "PStr", newVal: nil, want: func(s *S) {}},
+		{name: "text unmarshaler", path: "At", newVal: "2024-01-02T03:04:05Z", want: func(s *S) { s.At = at }},
+		{name: "text unmarshaler pointer", path: "PAt", newVal: []byte("2024-01-02T03:04:05Z"), want: func(s *S) { s.PAt = &at }},
+		{name: "text unmarshaler of value", path: "Addr", newVal: "127.0.0.1", want: func(s *S) { s.Addr = netip.MustParseAddr("127.0.0.1") }},
+		{name: "invalid text", path: "At", newVal: "yesterday", wantErr: new(*goval.ConversionError)},
+		{name: "assignable to interface", path: "Any", newVal: 1, want: func(s *S) { s.Any = 1 }},
+		{name: "int to string", path: "Str", newVal: 65, wantErr: new(*goval.TypeMismatchError)},
+		{name: "string to int", path: "I", newVal: "1", wantErr: new(*goval.TypeMismatchError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _ := goval.Parse(tt.path)
+			target := &S{}
+			err := goval.TrySetAny(target, path, tt.newVal)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("TrySetAny() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &S{}
+			tt.want(want)
+			if !reflect.DeepEqual(target, want) {
+				t.Errorf("TrySetAny() = %+v, want %+v", target, want)
+			}
+		})
+	}
+}
+
+func TestSetFuncPointerLeaf(t *testing.T) {
+	type member struct {
+		Name string
+	}
+	type team struct {
+		Members []*member
+		Leader  *member
+		Label   *string
+		Note    *string
+	}
+	label := "a"
+	target := &team{Members: []*member{{Name: "Alice"}}, Label: &label}
+	first := target.Members[0]
+
+	path, _ := goval.Parse("Members[0]")
+	goval.SetFunc(target, path, func(v member, _ goval.PathInfo) member {
+		v.Name += "!"
+		return v
+	})
+	if target.Members[0] != first || first.Name != "Alice!" {
+		t.Errorf("SetFunc() = %+v, want Alice! in the same pointer", target.Members[0])
+	}
+	if got := goval.GetAll[member](target, path); !reflect.DeepEqual(got, []member{{Name: "Alice!"}}) {
+		t.Errorf("GetAll() = %v, want [{Alice!}]", got)
+	}
+
+	path, _ = goval.Parse("Leader")
+	goval.SetFunc(target, path, func(v member, _ goval.PathInfo) member {
+		return member{Name: "Bob"}
+	})
+	if target.Leader == nil || target.Leader.Name != "Bob" {
+		t.Errorf("SetFunc() = %+v, want new pointer to Bob", target.Leader)
+	}
+
+	path, _ = goval.Parse("Label")
+	goval.Set(target, path, "b")
+	if label != "b" {
+		t.Errorf("Set() = %q, want b", label)
+	}
+	path, _ = goval.Parse("Note")
+	goval.Set(target, path, "c")
+	if target.Note == nil || *target.Note != "c" {
+		t.Errorf("Set() = %v, want pointer to c", target.Note)
+	}
+}
+
+func TestSetDeclaredTypes(t *testing.T) {
+	type status string
+	type database struct {
+		Host string
+	}
+	type config struct {
+		Timeout  time.Duration
+		Status   status
+		Database *database
+	}
+	target := &config{Database: &database{Host: "a"}}
+
+	if err := goval.TrySet(target, goval.Field("Timeout"), 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := goval.TrySet(target, goval.Field("Status"), status("ready")); err != nil {
+		t.Fatal(err)
+	}
+	db := &database{Host: "b"}
+	if err := goval.TrySet(target, goval.Field("Database"), db); err != nil {
+		t.Fatal(err)
+	}
+	want := &config{Timeout: 5 * time.Second, Status: "ready", Database: db}
+	if !reflect.DeepEqual(target, want) || target.Database != db {
+		t.Errorf("TrySet() = %+v, want %+v", target, want)
+	}
+
+	if got := goval.GetAll[time.Duration](target, goval.Field("Timeout")); !reflect.DeepEqual(got, []time.Duration{5 * time.Second}) {
+		t.Errorf("GetAll() = %v, want [5s]", got)
+	}
+	if got := goval.GetAll[status](target, goval.Field("Status")); !reflect.DeepEqual(got, []status{"ready"}) {
+		t.Errorf("GetAll() = %v, want [ready]", got)
+	}
+	if got := goval.GetAll[database](target, goval.Field("Database")); !reflect.DeepEqual(got, []database{{Host: "b"}}) {
+		t.Errorf("GetAll() = %v, want [{b}]", got)
+	}
+}